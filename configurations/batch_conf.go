@@ -0,0 +1,6 @@
+package configurations
+
+type BatchConfigurations struct {
+	MaxBatchSize int `yaml:"max_batch_size"`
+	WorkerCount  int `yaml:"worker_count"`
+}
@@ -0,0 +1,7 @@
+package configurations
+
+type CrawlConfigurations struct {
+	MaxDepth    int `yaml:"max_depth"`
+	MaxPages    int `yaml:"max_pages"`
+	WorkerCount int `yaml:"worker_count"`
+}
@@ -10,6 +10,9 @@ type Config struct {
 	AppConfig         *AppConfigurations         `yaml:"app_config"`
 	LogConfig         *LogConfigurations         `yaml:"log_config"`
 	WebAnalyzerConfig *WebAnalyzerConfigurations `yaml:"web_analyzer_configurations"`
+	JobConfig         *JobConfigurations         `yaml:"job_config"`
+	BatchConfig       *BatchConfigurations       `yaml:"batch_config"`
+	CrawlConfig       *CrawlConfigurations       `yaml:"crawl_config"`
 }
 
 func LoadConfigurations() *Config {
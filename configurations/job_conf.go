@@ -0,0 +1,8 @@
+package configurations
+
+type JobConfigurations struct {
+	WorkerCount      int `yaml:"worker_count"`
+	QueueSize        int `yaml:"queue_size"`
+	RetentionMinutes int `yaml:"retention_minutes"`
+	TimeoutMinutes   int `yaml:"timeout_minutes"`
+}
@@ -1,5 +1,22 @@
 package configurations
 
 type WebAnalyzerConfigurations struct {
-	MaxLinkAccessCheckerWorkerCount int `yaml:"max_link_access_checker_worker_count"`
+	MaxLinkAccessCheckerWorkerCount int      `yaml:"max_link_access_checker_worker_count"`
+	MaxRedirectHops                 int      `yaml:"max_redirect_hops"`
+	CertificateExpiryWarningDays    int      `yaml:"certificate_expiry_warning_days"`
+	TrackerListPath                 string   `yaml:"tracker_list_path"`
+	TechnologyRulesPath             string   `yaml:"technology_rules_path"`
+	LinkScope                       string   `yaml:"link_scope"`
+	LinkCheckMaxRedirects           int      `yaml:"link_check_max_redirects"`
+	AccessibleStatusCodes           []int    `yaml:"accessible_status_codes"`
+	MaxLinkChecksPerHost            int      `yaml:"max_link_checks_per_host"`
+	LinkCheckRequestsPerSecond      float64  `yaml:"link_check_requests_per_second"`
+	MaxRetryAfterSeconds            int      `yaml:"max_retry_after_seconds"`
+	RetryMaxAttempts                int      `yaml:"retry_max_attempts"`
+	RetryInitialBackoffMs           int      `yaml:"retry_initial_backoff_ms"`
+	RetryMaxBackoffMs               int      `yaml:"retry_max_backoff_ms"`
+	RetryBackoffMultiplier          float64  `yaml:"retry_backoff_multiplier"`
+	RetryJitter                     float64  `yaml:"retry_jitter"`
+	RetryableErrorClasses           []string `yaml:"retryable_error_classes"`
+	RetryableStatusCodes            []int    `yaml:"retryable_status_codes"`
 }
@@ -13,21 +13,27 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 )
 
 const webAnalyzerControllerLogPrefix = "web_analyzer_controller"
 
+var urlParseRegex = regexp.MustCompile(`^([a-zA-Z0-9-]+\.)+[a-zA-Z]{2,}$`)
+
 type ControllerV1 struct {
 	webAnalyzerService services.WebAnalyzerService
+	analysisJobService services.AnalysisJobService
 	logger             log_utils.LoggerInterface
 }
 
 func NewControllerV1(
 	webAnalyzerService services.WebAnalyzerService,
+	analysisJobService services.AnalysisJobService,
 	logger log_utils.LoggerInterface,
 ) *ControllerV1 {
 	return &ControllerV1{
 		webAnalyzerService: webAnalyzerService,
+		analysisJobService: analysisJobService,
 		logger:             logger,
 	}
 }
@@ -52,10 +58,8 @@ func (con *ControllerV1) AnalyzeController(c *gin.Context) {
 
 	con.logger.InfoWithContext(ctx, fmt.Sprintf("got new request url %v", inputURL), log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
 
-	urlParseRegex := regexp.MustCompile(`^([a-zA-Z0-9-]+\.)+[a-zA-Z]{2,}$`)
-
-	parsedURL, err := url.ParseRequestURI(inputURL) // checking if the URL is in valid format
-	if err != nil || !parsedURL.IsAbs() || !urlParseRegex.MatchString(parsedURL.Host) {
+	parsedURL, err := parseInputURL(inputURL)
+	if err != nil {
 		con.logger.ErrorWithContext(ctx, "failed to parse url", err, log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
 		con.logger.EndOfLog()
 		errorResponse := response_dtos.ErrorResponse{
@@ -66,7 +70,7 @@ func (con *ControllerV1) AnalyzeController(c *gin.Context) {
 		return
 	}
 
-	result, err := con.webAnalyzerService.AnalyzeUrl(ctx, parsedURL)
+	result, err := con.webAnalyzerService.AnalyzeUrl(ctx, parsedURL, jsonBody.Options)
 	if err != nil {
 		con.logger.ErrorWithContext(ctx, "failed to analyze url", err, log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
 		con.logger.EndOfLog()
@@ -91,3 +95,185 @@ func (con *ControllerV1) AnalyzeController(c *gin.Context) {
 	con.logger.EndOfLog()
 	c.JSON(http.StatusOK, result)
 }
+
+// SubmitJobController - validates the url in the request body and queues an analysis job for it.
+// responds with the job id right away, the result is fetched later using GetJobController
+func (con *ControllerV1) SubmitJobController(c *gin.Context) {
+
+	ctx := context.WithValue(context.Background(), "requestId", uuid.New().String())
+	var jsonBody request_dtos.UrlAnalyzerRequest
+
+	if err := c.BindJSON(&jsonBody); err != nil || jsonBody.Url == "" {
+		con.logger.ErrorWithContext(ctx, "invalid or missing json body", err, log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
+		c.JSON(http.StatusBadRequest, response_dtos.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid or missing json body",
+		})
+		return
+	}
+
+	parsedURL, err := parseInputURL(jsonBody.Url)
+	if err != nil {
+		con.logger.ErrorWithContext(ctx, "failed to parse url", err, log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
+		con.logger.EndOfLog()
+		c.JSON(http.StatusBadRequest, response_dtos.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "failed to parse url",
+		})
+		return
+	}
+
+	job, err := con.analysisJobService.SubmitJob(ctx, parsedURL, jsonBody.Options)
+	if err != nil {
+		con.logger.ErrorWithContext(ctx, "failed to submit analysis job", err, log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
+		con.logger.EndOfLog()
+		con.respondWithError(c, err, "failed to submit analysis job")
+		return
+	}
+
+	con.logger.InfoWithContext(ctx, fmt.Sprintf("submitted analysis job %v for url %v", job.JobId, jsonBody.Url), log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
+	con.logger.EndOfLog()
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetJobController - returns the status, progress and result of the analysis job given in the path
+func (con *ControllerV1) GetJobController(c *gin.Context) {
+
+	ctx := context.WithValue(context.Background(), "requestId", uuid.New().String())
+	jobId := c.Param("id")
+
+	job, err := con.analysisJobService.GetJob(ctx, jobId)
+	if err != nil {
+		con.logger.ErrorWithContext(ctx, "failed to get analysis job", err, log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
+		con.logger.EndOfLog()
+		con.respondWithError(c, err, "failed to get analysis job")
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// BatchAnalyzeController - validates every url in the request body and queues a batch job for them.
+// the urls which fail the validation are reported with an error in their result and the others are still analyzed.
+// responds with the job id right away, the result of each url and the summary of totals are fetched
+// later using GetJobController
+func (con *ControllerV1) BatchAnalyzeController(c *gin.Context) {
+
+	ctx := context.WithValue(context.Background(), "requestId", uuid.New().String())
+	var jsonBody request_dtos.BatchAnalyzerRequest
+
+	if err := c.BindJSON(&jsonBody); err != nil || len(jsonBody.Urls) == 0 {
+		con.logger.ErrorWithContext(ctx, "invalid or missing json body", err, log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
+		c.JSON(http.StatusBadRequest, response_dtos.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid or missing json body",
+		})
+		return
+	}
+
+	con.logger.InfoWithContext(ctx, fmt.Sprintf("got new batch request with %v urls", len(jsonBody.Urls)), log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
+
+	parsedURLs := make([]*url.URL, len(jsonBody.Urls))
+	for i, urlRequest := range jsonBody.Urls {
+		parsedURL, err := parseInputURL(urlRequest.Url)
+		if err != nil {
+			con.logger.ErrorWithContext(ctx, fmt.Sprintf("failed to parse url %v of the batch", urlRequest.Url), err, log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
+			continue // left nil so the batch reports the url as invalid
+		}
+		parsedURLs[i] = parsedURL
+	}
+
+	job, err := con.analysisJobService.SubmitBatchJob(ctx, parsedURLs, jsonBody)
+	if err != nil {
+		con.logger.ErrorWithContext(ctx, "failed to submit batch job", err, log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
+		con.logger.EndOfLog()
+		con.respondWithError(c, err, "failed to submit batch job")
+		return
+	}
+
+	con.logger.InfoWithContext(ctx, fmt.Sprintf("submitted batch job %v for %v urls", job.JobId, len(jsonBody.Urls)), log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
+	con.logger.EndOfLog()
+	c.JSON(http.StatusAccepted, job)
+}
+
+// CrawlController - validates the crawl request and queues a crawl job for the site starting from the url in the request.
+// responds with the job id right away, the result of each crawled page and the aggregated counts of the site
+// are fetched later using GetJobController
+func (con *ControllerV1) CrawlController(c *gin.Context) {
+
+	ctx := context.WithValue(context.Background(), "requestId", uuid.New().String())
+	var jsonBody request_dtos.CrawlRequest
+
+	if err := c.BindJSON(&jsonBody); err != nil || jsonBody.Url == "" {
+		con.logger.ErrorWithContext(ctx, "invalid or missing json body", err, log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
+		c.JSON(http.StatusBadRequest, response_dtos.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid or missing json body",
+		})
+		return
+	}
+
+	con.logger.InfoWithContext(ctx, fmt.Sprintf("got new crawl request url %v", jsonBody.Url), log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
+
+	parsedURL, err := parseInputURL(jsonBody.Url)
+	if err != nil {
+		con.logger.ErrorWithContext(ctx, "failed to parse url", err, log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
+		con.logger.EndOfLog()
+		c.JSON(http.StatusBadRequest, response_dtos.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "failed to parse url",
+		})
+		return
+	}
+
+	if jsonBody.MaxDepth < 0 || jsonBody.MaxPages < 0 || (jsonBody.PathPrefix != "" && !strings.HasPrefix(jsonBody.PathPrefix, "/")) {
+		err = fmt.Errorf("max_depth: %v, max_pages: %v, path_prefix: %v", jsonBody.MaxDepth, jsonBody.MaxPages, jsonBody.PathPrefix)
+		con.logger.ErrorWithContext(ctx, "invalid crawl limits", err, log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
+		con.logger.EndOfLog()
+		c.JSON(http.StatusBadRequest, response_dtos.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid crawl limits",
+		})
+		return
+	}
+
+	job, err := con.analysisJobService.SubmitCrawlJob(ctx, parsedURL, jsonBody)
+	if err != nil {
+		con.logger.ErrorWithContext(ctx, "failed to submit crawl job", err, log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
+		con.logger.EndOfLog()
+		con.respondWithError(c, err, "failed to submit crawl job")
+		return
+	}
+
+	con.logger.InfoWithContext(ctx, fmt.Sprintf("submitted crawl job %v for url %v", job.JobId, jsonBody.Url), log_utils.SetLogFile(webAnalyzerControllerLogPrefix))
+	con.logger.EndOfLog()
+	c.JSON(http.StatusAccepted, job)
+}
+
+// respondWithError - responds with the code of a CustomError, or with an internal server error for other errors
+func (con *ControllerV1) respondWithError(c *gin.Context, err error, message string) {
+	if customErr, ok := err.(*custom_errors.CustomError); ok {
+		c.JSON(customErr.Code, response_dtos.ErrorResponse{
+			Code:    customErr.Code,
+			Message: customErr.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, response_dtos.ErrorResponse{
+		Code:    http.StatusInternalServerError,
+		Message: fmt.Sprintf("%v: %v", message, err.Error()),
+	})
+}
+
+// parseInputURL - checks if the url is an absolute url in valid format with a valid host name
+func parseInputURL(inputURL string) (*url.URL, error) {
+	parsedURL, err := url.ParseRequestURI(inputURL)
+	if err != nil {
+		return nil, err
+	}
+	if !parsedURL.IsAbs() || !urlParseRegex.MatchString(parsedURL.Host) {
+		return nil, fmt.Errorf("invalid url %v", inputURL)
+	}
+	return parsedURL, nil
+}
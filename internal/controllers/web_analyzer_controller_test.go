@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/DaminduDilsara/web-analyzer/custom_errors"
@@ -43,7 +44,7 @@ func TestAnalyzeController(t *testing.T) {
 				"login_form":         false,
 			},
 			mockSetup: func(s *mocks.MockWebAnalyzerService) {
-				s.EXPECT().AnalyzeUrl(gomock.Any(), gomock.Any()).Return(&response_dtos.UrlAnalyzerResponse{
+				s.EXPECT().AnalyzeUrl(gomock.Any(), gomock.Any(), gomock.Any()).Return(&response_dtos.UrlAnalyzerResponse{
 					HTMLVersion:       "HTML5",
 					Title:             "Example",
 					Headings:          map[string]int{},
@@ -95,7 +96,7 @@ func TestAnalyzeController(t *testing.T) {
 				"message": "internal error: <nil>",
 			},
 			mockSetup: func(s *mocks.MockWebAnalyzerService) {
-				s.EXPECT().AnalyzeUrl(gomock.Any(), gomock.Any()).Return(nil, custom_errors.NewCustomError(http.StatusInternalServerError, "internal error", nil))
+				s.EXPECT().AnalyzeUrl(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, custom_errors.NewCustomError(http.StatusInternalServerError, "internal error", nil))
 			},
 		},
 		{
@@ -107,7 +108,7 @@ func TestAnalyzeController(t *testing.T) {
 				"message": "server not found for the given url or domain does not exist: <nil>",
 			},
 			mockSetup: func(s *mocks.MockWebAnalyzerService) {
-				s.EXPECT().AnalyzeUrl(gomock.Any(), gomock.Any()).Return(nil, custom_errors.NewCustomError(http.StatusNotFound, "server not found for the given url or domain does not exist", nil))
+				s.EXPECT().AnalyzeUrl(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, custom_errors.NewCustomError(http.StatusNotFound, "server not found for the given url or domain does not exist", nil))
 			},
 		},
 		{
@@ -119,7 +120,7 @@ func TestAnalyzeController(t *testing.T) {
 				"message": "failed to analyze url: http://example.com error: some generic error",
 			},
 			mockSetup: func(s *mocks.MockWebAnalyzerService) {
-				s.EXPECT().AnalyzeUrl(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("some generic error"))
+				s.EXPECT().AnalyzeUrl(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("some generic error"))
 			},
 		},
 		{
@@ -141,7 +142,7 @@ func TestAnalyzeController(t *testing.T) {
 				"message": "unexpected HTTP status code: <nil>",
 			},
 			mockSetup: func(s *mocks.MockWebAnalyzerService) {
-				s.EXPECT().AnalyzeUrl(gomock.Any(), gomock.Any()).Return(nil, custom_errors.NewCustomError(http.StatusNotFound, "unexpected HTTP status code", nil))
+				s.EXPECT().AnalyzeUrl(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, custom_errors.NewCustomError(http.StatusNotFound, "unexpected HTTP status code", nil))
 			},
 		},
 	}
@@ -154,7 +155,7 @@ func TestAnalyzeController(t *testing.T) {
 			mockService := mocks.NewMockWebAnalyzerService(ctrl)
 			tt.mockSetup(mockService)
 
-			controller := NewControllerV1(mockService, logger)
+			controller := NewControllerV1(mockService, nil, logger)
 
 			var bodyBytes []byte
 			switch v := tt.requestBody.(type) {
@@ -183,3 +184,388 @@ func TestAnalyzeController(t *testing.T) {
 		})
 	}
 }
+
+func TestSubmitJobController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := log_utils.InitConsoleLogger()
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		expectedStatus int
+		expectedBody   map[string]interface{}
+		mockSetup      func(*mocks.MockAnalysisJobService)
+	}{
+		{
+			name:           "Valid Request",
+			requestBody:    request_dtos.UrlAnalyzerRequest{Url: "http://example.com"},
+			expectedStatus: http.StatusAccepted,
+			expectedBody: map[string]interface{}{
+				"job_id": "job-1",
+				"url":    "http://example.com",
+				"status": "queued",
+			},
+			mockSetup: func(s *mocks.MockAnalysisJobService) {
+				s.EXPECT().SubmitJob(gomock.Any(), gomock.Any(), gomock.Any()).Return(&response_dtos.AnalysisJobResponse{
+					JobId:  "job-1",
+					Url:    "http://example.com",
+					Status: "queued",
+				}, nil)
+			},
+		},
+		{
+			name:           "Invalid JSON",
+			requestBody:    "not-json",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"code":    float64(http.StatusBadRequest),
+				"message": "invalid or missing json body",
+			},
+			mockSetup: func(s *mocks.MockAnalysisJobService) {},
+		},
+		{
+			name:           "Bad URL",
+			requestBody:    request_dtos.UrlAnalyzerRequest{Url: "bad_url"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"code":    float64(http.StatusBadRequest),
+				"message": "failed to parse url",
+			},
+			mockSetup: func(s *mocks.MockAnalysisJobService) {},
+		},
+		{
+			name:           "Job Queue Full",
+			requestBody:    request_dtos.UrlAnalyzerRequest{Url: "http://example.com"},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody: map[string]interface{}{
+				"code":    float64(http.StatusServiceUnavailable),
+				"message": "job queue is full: <nil>",
+			},
+			mockSetup: func(s *mocks.MockAnalysisJobService) {
+				s.EXPECT().SubmitJob(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, custom_errors.NewCustomError(http.StatusServiceUnavailable, "job queue is full", nil))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockJobService := mocks.NewMockAnalysisJobService(ctrl)
+			tt.mockSetup(mockJobService)
+
+			controller := NewControllerV1(nil, mockJobService, logger)
+
+			var bodyBytes []byte
+			switch v := tt.requestBody.(type) {
+			case string:
+				bodyBytes = []byte(v)
+			default:
+				bodyBytes, _ = json.Marshal(v)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", bytes.NewReader(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			controller.SubmitJobController(c)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var resp map[string]interface{}
+			_ = json.Unmarshal(w.Body.Bytes(), &resp)
+			for k, v := range tt.expectedBody {
+				assert.Equal(t, v, resp[k], "field %s mismatch", k)
+			}
+		})
+	}
+}
+
+func TestGetJobController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := log_utils.InitConsoleLogger()
+
+	tests := []struct {
+		name           string
+		jobId          string
+		expectedStatus int
+		expectedBody   map[string]interface{}
+		mockSetup      func(*mocks.MockAnalysisJobService)
+	}{
+		{
+			name:           "Running Job",
+			jobId:          "job-1",
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"job_id": "job-1",
+				"status": "running",
+				"progress": map[string]interface{}{
+					"links_checked": float64(3),
+					"total_links":   float64(10),
+				},
+			},
+			mockSetup: func(s *mocks.MockAnalysisJobService) {
+				s.EXPECT().GetJob(gomock.Any(), "job-1").Return(&response_dtos.AnalysisJobResponse{
+					JobId:    "job-1",
+					Status:   "running",
+					Progress: response_dtos.AnalysisJobProgress{LinksChecked: 3, TotalLinks: 10},
+				}, nil)
+			},
+		},
+		{
+			name:           "Unknown Job",
+			jobId:          "unknown",
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"code":    float64(http.StatusNotFound),
+				"message": "job not found: <nil>",
+			},
+			mockSetup: func(s *mocks.MockAnalysisJobService) {
+				s.EXPECT().GetJob(gomock.Any(), "unknown").Return(nil, custom_errors.NewCustomError(http.StatusNotFound, "job not found", nil))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockJobService := mocks.NewMockAnalysisJobService(ctrl)
+			tt.mockSetup(mockJobService)
+
+			controller := NewControllerV1(nil, mockJobService, logger)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs/"+tt.jobId, nil)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			c.Params = gin.Params{{Key: "id", Value: tt.jobId}}
+
+			controller.GetJobController(c)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var resp map[string]interface{}
+			_ = json.Unmarshal(w.Body.Bytes(), &resp)
+			for k, v := range tt.expectedBody {
+				assert.Equal(t, v, resp[k], "field %s mismatch", k)
+			}
+		})
+	}
+}
+
+func TestBatchAnalyzeController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := log_utils.InitConsoleLogger()
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		expectedStatus int
+		expectedBody   map[string]interface{}
+		mockSetup      func(*mocks.MockAnalysisJobService)
+	}{
+		{
+			name: "Valid Request",
+			requestBody: request_dtos.BatchAnalyzerRequest{Urls: []request_dtos.UrlAnalyzerRequest{
+				{Url: "http://example.com"},
+				{Url: "http://example.org", Options: request_dtos.AnalyzeOptions{SkipLinkCheck: true}},
+			}},
+			expectedStatus: http.StatusAccepted,
+			expectedBody: map[string]interface{}{
+				"job_id": "batch-1",
+				"type":   "batch",
+				"status": "queued",
+			},
+			mockSetup: func(s *mocks.MockAnalysisJobService) {
+				exampleCom, _ := url.ParseRequestURI("http://example.com")
+				exampleOrg, _ := url.ParseRequestURI("http://example.org")
+				s.EXPECT().SubmitBatchJob(gomock.Any(), []*url.URL{exampleCom, exampleOrg}, gomock.Any()).Return(&response_dtos.AnalysisJobResponse{
+					JobId:  "batch-1",
+					Type:   "batch",
+					Status: "queued",
+				}, nil)
+			},
+		},
+		{
+			name:           "Empty Batch",
+			requestBody:    request_dtos.BatchAnalyzerRequest{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"code":    float64(http.StatusBadRequest),
+				"message": "invalid or missing json body",
+			},
+			mockSetup: func(s *mocks.MockAnalysisJobService) {},
+		},
+		{
+			name: "Bad URL in Batch",
+			requestBody: request_dtos.BatchAnalyzerRequest{Urls: []request_dtos.UrlAnalyzerRequest{
+				{Url: "http://example.com"},
+				{Url: "bad_url"},
+			}},
+			expectedStatus: http.StatusAccepted,
+			expectedBody: map[string]interface{}{
+				"job_id": "batch-2",
+				"status": "queued",
+			},
+			mockSetup: func(s *mocks.MockAnalysisJobService) {
+				// the invalid url is passed as nil and reported in its result by the batch
+				exampleCom, _ := url.ParseRequestURI("http://example.com")
+				s.EXPECT().SubmitBatchJob(gomock.Any(), []*url.URL{exampleCom, nil}, gomock.Any()).Return(&response_dtos.AnalysisJobResponse{
+					JobId:  "batch-2",
+					Type:   "batch",
+					Status: "queued",
+				}, nil)
+			},
+		},
+		{
+			name: "Batch Too Large",
+			requestBody: request_dtos.BatchAnalyzerRequest{Urls: []request_dtos.UrlAnalyzerRequest{
+				{Url: "http://example.com"},
+			}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"code":    float64(http.StatusBadRequest),
+				"message": "batch size exceeds the limit: <nil>",
+			},
+			mockSetup: func(s *mocks.MockAnalysisJobService) {
+				s.EXPECT().SubmitBatchJob(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, custom_errors.NewCustomError(http.StatusBadRequest, "batch size exceeds the limit", nil))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockJobService := mocks.NewMockAnalysisJobService(ctrl)
+			tt.mockSetup(mockJobService)
+
+			controller := NewControllerV1(nil, mockJobService, logger)
+
+			bodyBytes, _ := json.Marshal(tt.requestBody)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/batch", bytes.NewReader(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			controller.BatchAnalyzeController(c)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var resp map[string]interface{}
+			_ = json.Unmarshal(w.Body.Bytes(), &resp)
+			for k, v := range tt.expectedBody {
+				assert.Equal(t, v, resp[k], "field %s mismatch", k)
+			}
+		})
+	}
+}
+
+func TestCrawlController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := log_utils.InitConsoleLogger()
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		expectedStatus int
+		expectedBody   map[string]interface{}
+		mockSetup      func(*mocks.MockAnalysisJobService)
+	}{
+		{
+			name:           "Valid Request",
+			requestBody:    request_dtos.CrawlRequest{Url: "http://example.com", MaxDepth: 2, MaxPages: 10, PathPrefix: "/docs"},
+			expectedStatus: http.StatusAccepted,
+			expectedBody: map[string]interface{}{
+				"job_id": "crawl-1",
+				"type":   "crawl",
+				"url":    "http://example.com",
+				"status": "queued",
+			},
+			mockSetup: func(s *mocks.MockAnalysisJobService) {
+				s.EXPECT().SubmitCrawlJob(gomock.Any(), gomock.Any(), request_dtos.CrawlRequest{Url: "http://example.com", MaxDepth: 2, MaxPages: 10, PathPrefix: "/docs"}).Return(&response_dtos.AnalysisJobResponse{
+					JobId:  "crawl-1",
+					Type:   "crawl",
+					Url:    "http://example.com",
+					Status: "queued",
+				}, nil)
+			},
+		},
+		{
+			name:           "Bad URL",
+			requestBody:    request_dtos.CrawlRequest{Url: "bad_url"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"code":    float64(http.StatusBadRequest),
+				"message": "failed to parse url",
+			},
+			mockSetup: func(s *mocks.MockAnalysisJobService) {},
+		},
+		{
+			name:           "Negative Depth",
+			requestBody:    request_dtos.CrawlRequest{Url: "http://example.com", MaxDepth: -1},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"code":    float64(http.StatusBadRequest),
+				"message": "invalid crawl limits",
+			},
+			mockSetup: func(s *mocks.MockAnalysisJobService) {},
+		},
+		{
+			name:           "Relative Path Prefix",
+			requestBody:    request_dtos.CrawlRequest{Url: "http://example.com", PathPrefix: "docs"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"code":    float64(http.StatusBadRequest),
+				"message": "invalid crawl limits",
+			},
+			mockSetup: func(s *mocks.MockAnalysisJobService) {},
+		},
+		{
+			name:           "Queue Full",
+			requestBody:    request_dtos.CrawlRequest{Url: "http://example.com"},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody: map[string]interface{}{
+				"code":    float64(http.StatusServiceUnavailable),
+				"message": "job queue is full: <nil>",
+			},
+			mockSetup: func(s *mocks.MockAnalysisJobService) {
+				s.EXPECT().SubmitCrawlJob(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, custom_errors.NewCustomError(http.StatusServiceUnavailable, "job queue is full", nil))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockJobService := mocks.NewMockAnalysisJobService(ctrl)
+			tt.mockSetup(mockJobService)
+
+			controller := NewControllerV1(nil, mockJobService, logger)
+
+			bodyBytes, _ := json.Marshal(tt.requestBody)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/crawl", bytes.NewReader(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			controller.CrawlController(c)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var resp map[string]interface{}
+			_ = json.Unmarshal(w.Body.Bytes(), &resp)
+			for k, v := range tt.expectedBody {
+				assert.Equal(t, v, resp[k], "field %s mismatch", k)
+			}
+		})
+	}
+}
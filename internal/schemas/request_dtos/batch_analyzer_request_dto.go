@@ -0,0 +1,5 @@
+package request_dtos
+
+type BatchAnalyzerRequest struct {
+	Urls []UrlAnalyzerRequest `json:"urls"`
+}
@@ -0,0 +1,9 @@
+package request_dtos
+
+type CrawlRequest struct {
+	Url        string         `json:"url"`
+	MaxDepth   int            `json:"max_depth"`
+	MaxPages   int            `json:"max_pages"`
+	PathPrefix string         `json:"path_prefix"`
+	Options    AnalyzeOptions `json:"options"`
+}
@@ -1,5 +1,11 @@
 package request_dtos
 
 type UrlAnalyzerRequest struct {
-	Url string `json:"url"`
+	Url     string         `json:"url"`
+	Options AnalyzeOptions `json:"options"`
+}
+
+type AnalyzeOptions struct {
+	SkipLinkCheck  bool `json:"skip_link_check"`
+	CheckResources bool `json:"check_resources"`
 }
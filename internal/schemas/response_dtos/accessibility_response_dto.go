@@ -0,0 +1,7 @@
+package response_dtos
+
+type AccessibilityAnalysis struct {
+	Lang    string          `json:"lang"`
+	Summary map[string]int  `json:"summary"`
+	Issues  []AnalysisIssue `json:"issues"`
+}
@@ -0,0 +1,8 @@
+package response_dtos
+
+type AnalysisIssue struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Path     string `json:"path,omitempty"`
+}
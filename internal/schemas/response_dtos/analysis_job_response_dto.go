@@ -0,0 +1,24 @@
+package response_dtos
+
+import "time"
+
+type AnalysisJobResponse struct {
+	JobId       string                 `json:"job_id"`
+	Type        string                 `json:"type"`
+	Url         string                 `json:"url,omitempty"`
+	Status      string                 `json:"status"`
+	Progress    AnalysisJobProgress    `json:"progress"`
+	Result      *UrlAnalyzerResponse   `json:"result,omitempty"`
+	BatchResult *BatchAnalyzerResponse `json:"batch_result,omitempty"`
+	CrawlResult *CrawlResponse         `json:"crawl_result,omitempty"`
+	Error       *ErrorResponse         `json:"error,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+type AnalysisJobProgress struct {
+	LinksChecked int `json:"links_checked"`
+	TotalLinks   int `json:"total_links"`
+	UrlsAnalyzed int `json:"urls_analyzed,omitempty"`
+	TotalUrls    int `json:"total_urls,omitempty"`
+}
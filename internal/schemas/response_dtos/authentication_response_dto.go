@@ -0,0 +1,41 @@
+package response_dtos
+
+type AuthenticationAnalysis struct {
+	LoginDetected     bool            `json:"login_detected"`
+	PasswordForm      bool            `json:"password_form"`
+	IdentifierFirst   bool            `json:"identifier_first"`
+	SSOButtons        []SSOButton     `json:"sso_buttons"`
+	OAuthEndpoints    []OAuthEndpoint `json:"oauth_endpoints"`
+	SAMLEndpoints     []AuthEndpoint  `json:"saml_endpoints"`
+	Captchas          []Captcha       `json:"captchas"`
+	OneTimeCodeFields []string        `json:"one_time_code_fields"`
+}
+
+type SSOButton struct {
+	Provider string `json:"provider"`
+	Text     string `json:"text"`
+	Url      string `json:"url,omitempty"`
+	Path     string `json:"path"`
+}
+
+type OAuthEndpoint struct {
+	Url           string   `json:"url"`
+	Provider      string   `json:"provider,omitempty"`
+	ClientId      string   `json:"client_id,omitempty"`
+	ResponseType  string   `json:"response_type,omitempty"`
+	Scopes        []string `json:"scopes"`
+	OpenIDConnect bool     `json:"openid_connect"`
+	PKCE          bool     `json:"pkce"`
+	Path          string   `json:"path"`
+}
+
+type AuthEndpoint struct {
+	Url  string `json:"url"`
+	Path string `json:"path"`
+}
+
+type Captcha struct {
+	Provider string `json:"provider"`
+	SiteKey  string `json:"site_key,omitempty"`
+	Path     string `json:"path"`
+}
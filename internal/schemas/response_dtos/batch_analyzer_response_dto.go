@@ -0,0 +1,23 @@
+package response_dtos
+
+type BatchAnalyzerResponse struct {
+	Results []BatchAnalyzerResult `json:"results"`
+	Summary BatchAnalyzerSummary  `json:"summary"`
+}
+
+type BatchAnalyzerResult struct {
+	Url    string               `json:"url"`
+	Result *UrlAnalyzerResponse `json:"result,omitempty"`
+	Error  *ErrorResponse       `json:"error,omitempty"`
+}
+
+type BatchAnalyzerSummary struct {
+	TotalUrls         int   `json:"total_urls"`
+	Succeeded         int   `json:"succeeded"`
+	Failed            int   `json:"failed"`
+	InternalLinks     int   `json:"internal_links"`
+	ExternalLinks     int   `json:"external_links"`
+	InaccessibleLinks int   `json:"inaccessible_links"`
+	LoginForms        int   `json:"login_forms"`
+	DurationMs        int64 `json:"duration_ms"`
+}
@@ -0,0 +1,22 @@
+package response_dtos
+
+import "time"
+
+type CookieAnalysis struct {
+	Cookies []CookieDetails `json:"cookies"`
+	Issues  []AnalysisIssue `json:"issues"`
+}
+
+type CookieDetails struct {
+	Name          string     `json:"name"`
+	Domain        string     `json:"domain"`
+	Path          string     `json:"path"`
+	Expires       *time.Time `json:"expires,omitempty"`
+	MaxAge        int        `json:"max_age,omitempty"`
+	SessionCookie bool       `json:"session_cookie"`
+	Secure        bool       `json:"secure"`
+	HttpOnly      bool       `json:"http_only"`
+	SameSite      string     `json:"same_site"`
+	Size          int        `json:"size"`
+	LooksLikeAuth bool       `json:"looks_like_auth"`
+}
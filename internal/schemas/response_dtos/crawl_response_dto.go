@@ -0,0 +1,34 @@
+package response_dtos
+
+type CrawlResponse struct {
+	StartUrl   string            `json:"start_url"`
+	MaxDepth   int               `json:"max_depth"`
+	MaxPages   int               `json:"max_pages"`
+	PathPrefix string            `json:"path_prefix"`
+	Pages      []CrawlPageResult `json:"pages"`
+	Summary    CrawlSummary      `json:"summary"`
+}
+
+type CrawlPageResult struct {
+	Url    string               `json:"url"`
+	Depth  int                  `json:"depth"`
+	Result *UrlAnalyzerResponse `json:"result,omitempty"`
+	Error  *ErrorResponse       `json:"error,omitempty"`
+}
+
+type CrawlSummary struct {
+	PagesCrawled       int            `json:"pages_crawled"`
+	PagesFailed        int            `json:"pages_failed"`
+	PageBudgetReached  bool           `json:"page_budget_reached"`
+	DeepestLevel       int            `json:"deepest_level"`
+	InternalLinks      int            `json:"internal_links"`
+	ExternalLinks      int            `json:"external_links"`
+	InaccessibleLinks  int            `json:"inaccessible_links"`
+	MalformedLinks     int            `json:"malformed_links"`
+	RestrictedLinks    int            `json:"restricted_links"`
+	RateLimitedLinks   int            `json:"rate_limited_links"`
+	PagesWithLoginForm int            `json:"pages_with_login_form"`
+	Headings           map[string]int `json:"headings"`
+	HTMLVersions       map[string]int `json:"html_versions"`
+	DurationMs         int64          `json:"duration_ms"`
+}
@@ -0,0 +1,11 @@
+package response_dtos
+
+type Doctype struct {
+	Present  bool   `json:"present"`
+	Raw      string `json:"raw"`
+	Name     string `json:"name"`
+	PublicId string `json:"public_id"`
+	SystemId string `json:"system_id"`
+	Version  string `json:"version"`
+	Mode     string `json:"mode"`
+}
@@ -0,0 +1,22 @@
+package response_dtos
+
+type FormAnalysis struct {
+	Forms  []Form          `json:"forms"`
+	Counts map[string]int  `json:"counts"`
+	Issues []AnalysisIssue `json:"issues"`
+}
+
+type Form struct {
+	Classification string      `json:"classification"`
+	Method         string      `json:"method"`
+	Action         string      `json:"action"`
+	Fields         []FormField `json:"fields"`
+	Path           string      `json:"path"`
+}
+
+type FormField struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	Required     bool   `json:"required"`
+	Autocomplete string `json:"autocomplete,omitempty"`
+}
@@ -0,0 +1,12 @@
+package response_dtos
+
+type HeadingOutline struct {
+	Outline []*HeadingNode  `json:"outline"`
+	Issues  []AnalysisIssue `json:"issues"`
+}
+
+type HeadingNode struct {
+	Level    int            `json:"level"`
+	Text     string         `json:"text"`
+	Children []*HeadingNode `json:"children,omitempty"`
+}
@@ -0,0 +1,22 @@
+package response_dtos
+
+type HTTPResponseMetadata struct {
+	StatusCode       int            `json:"status_code"`
+	Protocol         string         `json:"protocol"`
+	ContentType      string         `json:"content_type"`
+	Charset          string         `json:"charset"`
+	ContentEncoding  string         `json:"content_encoding"`
+	TransferredBytes int64          `json:"transferred_bytes"`
+	DecodedBytes     int64          `json:"decoded_bytes"`
+	Server           string         `json:"server"`
+	Timing           ResponseTiming `json:"timing"`
+}
+
+type ResponseTiming struct {
+	DNSMs      int64 `json:"dns_ms"`
+	ConnectMs  int64 `json:"connect_ms"`
+	TLSMs      int64 `json:"tls_ms"`
+	TTFBMs     int64 `json:"ttfb_ms"`
+	DownloadMs int64 `json:"download_ms"`
+	TotalMs    int64 `json:"total_ms"`
+}
@@ -0,0 +1,16 @@
+package response_dtos
+
+type MixedContentAnalysis struct {
+	Applicable   bool                  `json:"applicable"`
+	ActiveCount  int                   `json:"active_count"`
+	PassiveCount int                   `json:"passive_count"`
+	Findings     []MixedContentFinding `json:"findings"`
+}
+
+type MixedContentFinding struct {
+	Url       string `json:"url"`
+	Element   string `json:"element"`
+	Attribute string `json:"attribute"`
+	Type      string `json:"type"`
+	Path      string `json:"path"`
+}
@@ -0,0 +1,15 @@
+package response_dtos
+
+type RedirectAnalysis struct {
+	Hops     []RedirectHop   `json:"hops"`
+	HopCount int             `json:"hop_count"`
+	FinalUrl string          `json:"final_url"`
+	Issues   []AnalysisIssue `json:"issues"`
+}
+
+type RedirectHop struct {
+	Url              string `json:"url"`
+	StatusCode       int    `json:"status_code"`
+	Location         string `json:"location"`
+	CertificateError string `json:"certificate_error,omitempty"`
+}
@@ -0,0 +1,24 @@
+package response_dtos
+
+type ResourceInventory struct {
+	Resources             []Resource     `json:"resources"`
+	Counts                map[string]int `json:"counts"`
+	FirstParty            int            `json:"first_party"`
+	ThirdParty            int            `json:"third_party"`
+	Inline                int            `json:"inline"`
+	InaccessibleResources int            `json:"inaccessible_resources"`
+}
+
+type Resource struct {
+	Type        string `json:"type"`
+	Url         string `json:"url,omitempty"`
+	Inline      bool   `json:"inline"`
+	ThirdParty  bool   `json:"third_party"`
+	Async       bool   `json:"async,omitempty"`
+	Defer       bool   `json:"defer,omitempty"`
+	Integrity   string `json:"integrity,omitempty"`
+	CrossOrigin string `json:"crossorigin,omitempty"`
+	Path        string `json:"path"`
+	Status      string `json:"status,omitempty"`
+	StatusCode  int    `json:"status_code,omitempty"`
+}
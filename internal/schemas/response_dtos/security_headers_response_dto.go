@@ -0,0 +1,22 @@
+package response_dtos
+
+type SecurityHeadersAnalysis struct {
+	Grade   string                  `json:"grade"`
+	Score   int                     `json:"score"`
+	Headers []SecurityHeaderVerdict `json:"headers"`
+	CSP     ContentSecurityPolicy   `json:"csp"`
+	Issues  []AnalysisIssue         `json:"issues"`
+}
+
+type SecurityHeaderVerdict struct {
+	Header  string `json:"header"`
+	Value   string `json:"value"`
+	Verdict string `json:"verdict"`
+	Message string `json:"message"`
+}
+
+type ContentSecurityPolicy struct {
+	Directives   map[string][]string `json:"directives"`
+	UnsafeInline bool                `json:"unsafe_inline"`
+	UnsafeEval   bool                `json:"unsafe_eval"`
+}
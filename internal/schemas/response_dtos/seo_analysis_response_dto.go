@@ -0,0 +1,26 @@
+package response_dtos
+
+type SEOAnalysis struct {
+	Title             string          `json:"title"`
+	TitleLength       int             `json:"title_length"`
+	MetaDescription   string          `json:"meta_description"`
+	DescriptionLength int             `json:"description_length"`
+	Canonical         []string        `json:"canonical"`
+	Robots            RobotsMeta      `json:"robots"`
+	Hreflang          []HreflangLink  `json:"hreflang"`
+	Viewport          string          `json:"viewport"`
+	Charset           string          `json:"charset"`
+	Issues            []AnalysisIssue `json:"issues"`
+}
+
+type RobotsMeta struct {
+	Content    string   `json:"content"`
+	Directives []string `json:"directives"`
+	NoIndex    bool     `json:"noindex"`
+	NoFollow   bool     `json:"nofollow"`
+}
+
+type HreflangLink struct {
+	Lang string `json:"lang"`
+	Href string `json:"href"`
+}
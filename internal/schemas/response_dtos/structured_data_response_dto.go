@@ -0,0 +1,9 @@
+package response_dtos
+
+type StructuredData struct {
+	OpenGraph    map[string][]string                 `json:"open_graph"`
+	TwitterCard  map[string][]string                 `json:"twitter_card"`
+	JSONLD       map[string][]map[string]interface{} `json:"json_ld"`
+	JSONLDBlocks int                                 `json:"json_ld_blocks"`
+	Issues       []AnalysisIssue                     `json:"issues"`
+}
@@ -0,0 +1,9 @@
+package response_dtos
+
+type Technology struct {
+	Name       string   `json:"name"`
+	Category   string   `json:"category"`
+	Version    string   `json:"version,omitempty"`
+	Confidence int      `json:"confidence"`
+	Evidence   []string `json:"evidence"`
+}
@@ -0,0 +1,18 @@
+package response_dtos
+
+type ThirdPartyAnalysis struct {
+	FirstPartyDomain string             `json:"first_party_domain"`
+	Domains          []ThirdPartyDomain `json:"domains"`
+	Categories       map[string]int     `json:"categories"`
+	TrackerCount     int                `json:"tracker_count"`
+}
+
+type ThirdPartyDomain struct {
+	Domain             string   `json:"domain"`
+	Hosts              []string `json:"hosts"`
+	Category           string   `json:"category,omitempty"`
+	Tracker            bool     `json:"tracker"`
+	LinkReferences     int      `json:"link_references"`
+	ResourceReferences int      `json:"resource_references"`
+	TotalReferences    int      `json:"total_references"`
+}
@@ -0,0 +1,25 @@
+package response_dtos
+
+import "time"
+
+type TLSAnalysis struct {
+	Enabled         bool             `json:"enabled"`
+	Version         string           `json:"version,omitempty"`
+	CipherSuite     string           `json:"cipher_suite,omitempty"`
+	Subject         string           `json:"subject,omitempty"`
+	SANs            []string         `json:"sans,omitempty"`
+	Issuer          string           `json:"issuer,omitempty"`
+	NotBefore       *time.Time       `json:"not_before,omitempty"`
+	NotAfter        *time.Time       `json:"not_after,omitempty"`
+	DaysUntilExpiry int              `json:"days_until_expiry"`
+	Trusted         bool             `json:"trusted"`
+	HostnameMatch   bool             `json:"hostname_match"`
+	Chain           []TLSCertificate `json:"chain,omitempty"`
+	Issues          []AnalysisIssue  `json:"issues"`
+}
+
+type TLSCertificate struct {
+	Subject  string    `json:"subject"`
+	Issuer   string    `json:"issuer"`
+	NotAfter time.Time `json:"not_after"`
+}
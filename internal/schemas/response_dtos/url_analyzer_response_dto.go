@@ -1,11 +1,45 @@
 package response_dtos
 
 type UrlAnalyzerResponse struct {
-	HTMLVersion       string         `json:"html_version"`
-	Title             string         `json:"title"`
-	Headings          map[string]int `json:"headings"`
-	InternalLinks     int            `json:"internal_links"`
-	ExternalLinks     int            `json:"external_links"`
-	InaccessibleLinks int            `json:"inaccessible_links"`
-	LoginForm         bool           `json:"login_form"`
+	HTMLVersion       string                  `json:"html_version"`
+	Doctype           Doctype                 `json:"doctype"`
+	HTTPResponse      HTTPResponseMetadata    `json:"http_response"`
+	Redirects         RedirectAnalysis        `json:"redirects"`
+	SecurityHeaders   SecurityHeadersAnalysis `json:"security_headers"`
+	TLS               TLSAnalysis             `json:"tls"`
+	Cookies           CookieAnalysis          `json:"cookies"`
+	Title             string                  `json:"title"`
+	Headings          map[string]int          `json:"headings"`
+	HeadingOutline    HeadingOutline          `json:"heading_outline"`
+	InternalLinks     int                     `json:"internal_links"`
+	ExternalLinks     int                     `json:"external_links"`
+	NonHTTPLinks      map[string]int          `json:"non_http_links"`
+	InaccessibleLinks int                     `json:"inaccessible_links"`
+	MalformedLinks    int                     `json:"malformed_links"`
+	RestrictedLinks   int                     `json:"restricted_links"`
+	RateLimitedLinks  int                     `json:"rate_limited_links"`
+	Links             []LinkStatus            `json:"links"`
+	LoginForm         bool                    `json:"login_form"`
+	Forms             FormAnalysis            `json:"forms"`
+	Authentication    AuthenticationAnalysis  `json:"authentication"`
+	SEO               SEOAnalysis             `json:"seo"`
+	StructuredData    StructuredData          `json:"structured_data"`
+	Accessibility     AccessibilityAnalysis   `json:"accessibility"`
+	MixedContent      MixedContentAnalysis    `json:"mixed_content"`
+	Resources         ResourceInventory       `json:"resources"`
+	ThirdParties      ThirdPartyAnalysis      `json:"third_parties"`
+	Technologies      []Technology            `json:"technologies"`
+}
+
+type LinkStatus struct {
+	Href       string `json:"href"`
+	Url        string `json:"url"`
+	FinalUrl   string `json:"final_url,omitempty"`
+	Status     string `json:"status"`
+	Method     string `json:"method,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+	ErrorClass string `json:"error_class,omitempty"`
+	Error      string `json:"error,omitempty"`
+	LatencyMs  int64  `json:"latency_ms"`
+	Attempts   int    `json:"attempts"`
 }
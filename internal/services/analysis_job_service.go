@@ -0,0 +1,15 @@
+package services
+
+import (
+	"context"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/request_dtos"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"net/url"
+)
+
+type AnalysisJobService interface {
+	SubmitJob(ctx context.Context, parsedURL *url.URL, options request_dtos.AnalyzeOptions) (*response_dtos.AnalysisJobResponse, error)
+	SubmitBatchJob(ctx context.Context, parsedURLs []*url.URL, request request_dtos.BatchAnalyzerRequest) (*response_dtos.AnalysisJobResponse, error)
+	SubmitCrawlJob(ctx context.Context, parsedURL *url.URL, request request_dtos.CrawlRequest) (*response_dtos.AnalysisJobResponse, error)
+	GetJob(ctx context.Context, jobId string) (*response_dtos.AnalysisJobResponse, error)
+}
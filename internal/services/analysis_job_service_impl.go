@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/custom_errors"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/request_dtos"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/DaminduDilsara/web-analyzer/internal/web_analyzer_utils"
+	"github.com/google/uuid"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const analysisJobServiceLogPrefix = "analysis_job_service_impl"
+
+// statuses of an analysis job
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+)
+
+// types of an analysis job
+const (
+	JobTypeAnalysis = "analysis"
+	JobTypeBatch    = "batch"
+	JobTypeCrawl    = "crawl"
+)
+
+// analysisJob - a queued analysis of a url, a batch of urls or a site crawl. run does the analysis and stores its result in the job
+type analysisJob struct {
+	id          string
+	jobType     string
+	url         string
+	run         func(ctx context.Context, job *analysisJob) error
+	status      string
+	progress    response_dtos.AnalysisJobProgress
+	result      *response_dtos.UrlAnalyzerResponse
+	batchResult *response_dtos.BatchAnalyzerResponse
+	crawlResult *response_dtos.CrawlResponse
+	err         *response_dtos.ErrorResponse
+	createdAt   time.Time
+	updatedAt   time.Time
+}
+
+type analysisJobServiceImpl struct {
+	logger               log_utils.LoggerInterface
+	webAnalyzerService   WebAnalyzerService
+	batchAnalyzerService BatchAnalyzerService
+	jobConfig            *configurations.JobConfigurations
+	jobTimeout           time.Duration
+	queue                chan *analysisJob
+	mu                   sync.RWMutex
+	jobs                 map[string]*analysisJob
+}
+
+// NewAnalysisJobService - creates the job service and starts jobConfig.WorkerCount workers
+// which take the submitted jobs from a queue of size jobConfig.QueueSize. a job running longer than
+// jobConfig.TimeoutMinutes is cancelled, jobs are not timed out when it is zero
+func NewAnalysisJobService(
+	logger log_utils.LoggerInterface,
+	webAnalyzerService WebAnalyzerService,
+	batchAnalyzerService BatchAnalyzerService,
+	jobConfig *configurations.JobConfigurations,
+) AnalysisJobService {
+	service := &analysisJobServiceImpl{
+		logger:               logger,
+		webAnalyzerService:   webAnalyzerService,
+		batchAnalyzerService: batchAnalyzerService,
+		jobConfig:            jobConfig,
+		jobTimeout:           time.Duration(jobConfig.TimeoutMinutes) * time.Minute,
+		queue:                make(chan *analysisJob, jobConfig.QueueSize),
+		jobs:                 make(map[string]*analysisJob),
+	}
+
+	for i := 0; i < jobConfig.WorkerCount; i++ {
+		go service.worker()
+	}
+
+	return service
+}
+
+// SubmitJob - registers a new analysis job for the url and puts it in the job queue.
+// returns the queued job without waiting for the analysis
+func (a *analysisJobServiceImpl) SubmitJob(ctx context.Context, parsedURL *url.URL, options request_dtos.AnalyzeOptions) (*response_dtos.AnalysisJobResponse, error) {
+	return a.submit(ctx, JobTypeAnalysis, parsedURL.String(), func(ctx context.Context, job *analysisJob) error {
+		ctx = web_analyzer_utils.WithLinkProgress(ctx, func(checked int, total int) {
+			a.updateJob(job, func() {
+				job.progress.TotalLinks = total
+				if checked > job.progress.LinksChecked { // progress can be reported out of order by the link checkers
+					job.progress.LinksChecked = checked
+				}
+			})
+		})
+
+		result, err := a.webAnalyzerService.AnalyzeUrl(ctx, parsedURL, options)
+		if err != nil {
+			return err
+		}
+		a.updateJob(job, func() {
+			job.result = result
+		})
+		return nil
+	})
+}
+
+// SubmitBatchJob - registers a new batch job for the urls of the request and puts it in the job queue.
+// parsedURLs are the validated urls of request.Urls in the same order. batches rejected by the batch
+// analyzer service are not queued. returns the queued job without waiting for the analysis
+func (a *analysisJobServiceImpl) SubmitBatchJob(ctx context.Context, parsedURLs []*url.URL, request request_dtos.BatchAnalyzerRequest) (*response_dtos.AnalysisJobResponse, error) {
+	if err := a.batchAnalyzerService.ValidateBatch(ctx, request); err != nil {
+		return nil, err
+	}
+
+	return a.submit(ctx, JobTypeBatch, "", func(ctx context.Context, job *analysisJob) error {
+		ctx = withBatchProgress(ctx, func(analyzed int, total int) {
+			a.updateJob(job, func() {
+				job.progress.TotalUrls = total
+				if analyzed > job.progress.UrlsAnalyzed { // progress can be reported out of order by the batch workers
+					job.progress.UrlsAnalyzed = analyzed
+				}
+			})
+		})
+
+		result, err := a.batchAnalyzerService.AnalyzeBatch(ctx, parsedURLs, request)
+		if err != nil {
+			return err
+		}
+		a.updateJob(job, func() {
+			job.batchResult = result
+		})
+		return nil
+	})
+}
+
+// SubmitCrawlJob - registers a new crawl job for the site starting from the url and puts it in the job queue.
+// returns the queued job without waiting for the crawl
+func (a *analysisJobServiceImpl) SubmitCrawlJob(ctx context.Context, parsedURL *url.URL, request request_dtos.CrawlRequest) (*response_dtos.AnalysisJobResponse, error) {
+	return a.submit(ctx, JobTypeCrawl, parsedURL.String(), func(ctx context.Context, job *analysisJob) error {
+		result, err := a.webAnalyzerService.CrawlSite(ctx, parsedURL, request)
+		if err != nil {
+			return err
+		}
+		a.updateJob(job, func() {
+			job.crawlResult = result
+		})
+		return nil
+	})
+}
+
+// submit - registers a new job of the type which runs the given function and puts it in the job queue
+func (a *analysisJobServiceImpl) submit(ctx context.Context, jobType string, jobURL string, run func(ctx context.Context, job *analysisJob) error) (*response_dtos.AnalysisJobResponse, error) {
+	a.removeExpiredJobs()
+
+	now := time.Now()
+	job := &analysisJob{
+		id:        uuid.New().String(),
+		jobType:   jobType,
+		url:       jobURL,
+		run:       run,
+		status:    JobStatusQueued,
+		createdAt: now,
+		updatedAt: now,
+	}
+
+	a.mu.Lock()
+	select {
+	case a.queue <- job:
+		a.jobs[job.id] = job
+	default:
+		a.mu.Unlock()
+		err := fmt.Errorf("job queue reached its limit of %v jobs", a.jobConfig.QueueSize)
+		a.logger.ErrorWithContext(ctx, "unable to queue the analysis job", err, log_utils.SetLogFile(analysisJobServiceLogPrefix))
+		return nil, custom_errors.NewCustomError(http.StatusServiceUnavailable, "job queue is full", err)
+	}
+	response := job.toResponse()
+	a.mu.Unlock()
+
+	a.logger.InfoWithContext(ctx, fmt.Sprintf("queued %v job %v", job.jobType, job.id), log_utils.SetLogFile(analysisJobServiceLogPrefix))
+
+	return response, nil
+}
+
+// GetJob - returns the current status, progress and the result (once finished) of a job.
+// expired jobs are removed first, so a job is not returned after its retention
+func (a *analysisJobServiceImpl) GetJob(ctx context.Context, jobId string) (*response_dtos.AnalysisJobResponse, error) {
+	a.removeExpiredJobs()
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	job, ok := a.jobs[jobId]
+	if !ok {
+		err := fmt.Errorf("no job exists with the id %v", jobId)
+		a.logger.ErrorWithContext(ctx, "job not found", err, log_utils.SetLogFile(analysisJobServiceLogPrefix))
+		return nil, custom_errors.NewCustomError(http.StatusNotFound, "job not found", err)
+	}
+
+	return job.toResponse(), nil
+}
+
+// worker - runs the queued jobs one at a time
+func (a *analysisJobServiceImpl) worker() {
+	for job := range a.queue {
+		a.runJob(job)
+	}
+}
+
+func (a *analysisJobServiceImpl) runJob(job *analysisJob) {
+	ctx := context.WithValue(context.Background(), "requestId", job.id)
+	if a.jobTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.jobTimeout)
+		defer cancel()
+	}
+
+	a.updateJob(job, func() {
+		job.status = JobStatusRunning
+	})
+	a.logger.InfoWithContext(ctx, fmt.Sprintf("started %v job %v", job.jobType, job.id), log_utils.SetLogFile(analysisJobServiceLogPrefix))
+
+	if err := job.run(ctx, job); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = custom_errors.NewCustomError(http.StatusGatewayTimeout, fmt.Sprintf("the job did not finish within %v", a.jobTimeout), err)
+		}
+		a.logger.ErrorWithContext(ctx, fmt.Sprintf("%v job failed", job.jobType), err, log_utils.SetLogFile(analysisJobServiceLogPrefix))
+		a.logger.EndOfLog()
+		a.updateJob(job, func() {
+			job.status = JobStatusFailed
+			job.err = toErrorResponse(job.url, err)
+		})
+		return
+	}
+
+	a.logger.InfoWithContext(ctx, fmt.Sprintf("completed %v job %v", job.jobType, job.id), log_utils.SetLogFile(analysisJobServiceLogPrefix))
+	a.logger.EndOfLog()
+	a.updateJob(job, func() {
+		job.status = JobStatusCompleted
+	})
+}
+
+// updateJob - applies the update to the job while holding the lock of the job store
+func (a *analysisJobServiceImpl) updateJob(job *analysisJob, update func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	update()
+	job.updatedAt = time.Now()
+}
+
+// removeExpiredJobs - removes finished jobs which are older than jobConfig.RetentionMinutes
+func (a *analysisJobServiceImpl) removeExpiredJobs() {
+	expiry := time.Now().Add(-time.Duration(a.jobConfig.RetentionMinutes) * time.Minute)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for id, job := range a.jobs {
+		isFinished := job.status == JobStatusCompleted || job.status == JobStatusFailed
+		if isFinished && job.updatedAt.Before(expiry) {
+			delete(a.jobs, id)
+		}
+	}
+}
+
+func (j *analysisJob) toResponse() *response_dtos.AnalysisJobResponse {
+	return &response_dtos.AnalysisJobResponse{
+		JobId:       j.id,
+		Type:        j.jobType,
+		Url:         j.url,
+		Status:      j.status,
+		Progress:    j.progress,
+		Result:      j.result,
+		BatchResult: j.batchResult,
+		CrawlResult: j.crawlResult,
+		Error:       j.err,
+		CreatedAt:   j.createdAt,
+		UpdatedAt:   j.updatedAt,
+	}
+}
+
+// toErrorResponse - converts an error returned by the web analyzer service to an error response
+func toErrorResponse(inputURL string, err error) *response_dtos.ErrorResponse {
+	if analyzerErr, ok := err.(*custom_errors.CustomError); ok {
+		return &response_dtos.ErrorResponse{
+			Code:    analyzerErr.Code,
+			Message: analyzerErr.Error(),
+		}
+	}
+
+	return &response_dtos.ErrorResponse{
+		Code:    http.StatusInternalServerError,
+		Message: fmt.Sprintf("failed to analyze url: %v error: %v", inputURL, err.Error()),
+	}
+}
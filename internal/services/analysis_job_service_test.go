@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/custom_errors"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/request_dtos"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/DaminduDilsara/web-analyzer/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// waitForJob polls the job until it is finished or the timeout is reached
+func waitForJob(t *testing.T, service AnalysisJobService, jobId string) *response_dtos.AnalysisJobResponse {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := service.GetJob(context.Background(), jobId)
+		assert.Nil(t, err)
+		if job.Status == JobStatusCompleted || job.Status == JobStatusFailed {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %v did not finish in time", jobId)
+	return nil
+}
+
+func TestAnalysisJobService(t *testing.T) {
+	parsedURL, _ := url.Parse("http://test.test")
+	logger := log_utils.InitConsoleLogger()
+	jobConfig := &configurations.JobConfigurations{WorkerCount: 1, QueueSize: 10, RetentionMinutes: 60}
+
+	tests := []struct {
+		name           string
+		mockSetup      func(*mocks.MockWebAnalyzerService)
+		expectedStatus string
+		expectedResult *response_dtos.UrlAnalyzerResponse
+		expectedError  *response_dtos.ErrorResponse
+	}{
+		{
+			name: "Completed Job",
+			mockSetup: func(s *mocks.MockWebAnalyzerService) {
+				s.EXPECT().AnalyzeUrl(gomock.Any(), parsedURL, request_dtos.AnalyzeOptions{}).Return(&response_dtos.UrlAnalyzerResponse{Title: "Test Page"}, nil)
+			},
+			expectedStatus: JobStatusCompleted,
+			expectedResult: &response_dtos.UrlAnalyzerResponse{Title: "Test Page"},
+		},
+		{
+			name: "Failed Job",
+			mockSetup: func(s *mocks.MockWebAnalyzerService) {
+				s.EXPECT().AnalyzeUrl(gomock.Any(), parsedURL, request_dtos.AnalyzeOptions{}).Return(nil, custom_errors.NewCustomError(http.StatusBadGateway, "failed to connect to the given server", nil))
+			},
+			expectedStatus: JobStatusFailed,
+			expectedError: &response_dtos.ErrorResponse{
+				Code:    http.StatusBadGateway,
+				Message: "failed to connect to the given server: <nil>",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockWebAnalyzerService(ctrl)
+			tt.mockSetup(mockService)
+
+			service := NewAnalysisJobService(logger, mockService, nil, jobConfig)
+
+			submitted, err := service.SubmitJob(context.Background(), parsedURL, request_dtos.AnalyzeOptions{})
+			assert.Nil(t, err)
+			assert.NotEmpty(t, submitted.JobId)
+			assert.Equal(t, JobTypeAnalysis, submitted.Type)
+			assert.Equal(t, parsedURL.String(), submitted.Url)
+
+			job := waitForJob(t, service, submitted.JobId)
+			assert.Equal(t, tt.expectedStatus, job.Status)
+			assert.Equal(t, tt.expectedResult, job.Result)
+			assert.Equal(t, tt.expectedError, job.Error)
+		})
+	}
+}
+
+func TestAnalysisJobServiceErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parsedURL, _ := url.Parse("http://test.test")
+	logger := log_utils.InitConsoleLogger()
+
+	// no workers, so the submitted jobs stay in the queue
+	jobConfig := &configurations.JobConfigurations{WorkerCount: 0, QueueSize: 1, RetentionMinutes: 60}
+	mockBatchService := mocks.NewMockBatchAnalyzerService(ctrl)
+	mockBatchService.EXPECT().ValidateBatch(gomock.Any(), gomock.Any()).Return(custom_errors.NewCustomError(http.StatusBadRequest, "batch size exceeds the limit", nil))
+
+	service := NewAnalysisJobService(logger, mocks.NewMockWebAnalyzerService(ctrl), mockBatchService, jobConfig)
+
+	submitted, err := service.SubmitJob(context.Background(), parsedURL, request_dtos.AnalyzeOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, JobStatusQueued, submitted.Status)
+
+	_, err = service.SubmitJob(context.Background(), parsedURL, request_dtos.AnalyzeOptions{})
+	customErr, ok := err.(*custom_errors.CustomError)
+	if !ok {
+		t.Fatalf("error should be of type *CustomError, got %T: %v", err, err)
+	}
+	assert.Equal(t, http.StatusServiceUnavailable, customErr.Code)
+	assert.Equal(t, "job queue is full", customErr.Message)
+
+	_, err = service.GetJob(context.Background(), "unknown-job")
+	customErr, ok = err.(*custom_errors.CustomError)
+	if !ok {
+		t.Fatalf("error should be of type *CustomError, got %T: %v", err, err)
+	}
+	assert.Equal(t, http.StatusNotFound, customErr.Code)
+	assert.Equal(t, "job not found", customErr.Message)
+
+	// batches rejected by the batch service are not queued
+	_, err = service.SubmitBatchJob(context.Background(), []*url.URL{parsedURL}, request_dtos.BatchAnalyzerRequest{})
+	customErr, ok = err.(*custom_errors.CustomError)
+	if !ok {
+		t.Fatalf("error should be of type *CustomError, got %T: %v", err, err)
+	}
+	assert.Equal(t, http.StatusBadRequest, customErr.Code)
+	assert.Equal(t, "batch size exceeds the limit", customErr.Message)
+}
+
+func TestAnalysisJobServiceBatchJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parsedURL, _ := url.Parse("http://test.test")
+	parsedURLs := []*url.URL{parsedURL}
+	request := request_dtos.BatchAnalyzerRequest{Urls: []request_dtos.UrlAnalyzerRequest{{Url: "http://test.test"}}}
+	batchResult := &response_dtos.BatchAnalyzerResponse{
+		Results: []response_dtos.BatchAnalyzerResult{{Url: "http://test.test", Result: &response_dtos.UrlAnalyzerResponse{Title: "Test Page"}}},
+		Summary: response_dtos.BatchAnalyzerSummary{TotalUrls: 1, Succeeded: 1},
+	}
+
+	logger := log_utils.InitConsoleLogger()
+	jobConfig := &configurations.JobConfigurations{WorkerCount: 1, QueueSize: 10, RetentionMinutes: 60}
+
+	mockBatchService := mocks.NewMockBatchAnalyzerService(ctrl)
+	mockBatchService.EXPECT().ValidateBatch(gomock.Any(), request).Return(nil)
+	mockBatchService.EXPECT().AnalyzeBatch(gomock.Any(), parsedURLs, request).DoAndReturn(
+		func(ctx context.Context, parsedURLs []*url.URL, request request_dtos.BatchAnalyzerRequest) (*response_dtos.BatchAnalyzerResponse, error) {
+			reportBatchProgress(ctx, 1, 1)
+			return batchResult, nil
+		})
+
+	service := NewAnalysisJobService(logger, mocks.NewMockWebAnalyzerService(ctrl), mockBatchService, jobConfig)
+
+	submitted, err := service.SubmitBatchJob(context.Background(), parsedURLs, request)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, submitted.JobId)
+	assert.Equal(t, JobTypeBatch, submitted.Type)
+
+	job := waitForJob(t, service, submitted.JobId)
+	assert.Equal(t, JobStatusCompleted, job.Status)
+	assert.Equal(t, batchResult, job.BatchResult)
+	assert.Equal(t, response_dtos.AnalysisJobProgress{UrlsAnalyzed: 1, TotalUrls: 1}, job.Progress)
+	assert.Nil(t, job.Result)
+}
+
+func TestAnalysisJobServiceCrawlJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parsedURL, _ := url.Parse("http://test.test")
+	request := request_dtos.CrawlRequest{Url: "http://test.test", MaxDepth: 1}
+	crawlResult := &response_dtos.CrawlResponse{
+		StartUrl: "http://test.test",
+		MaxDepth: 1,
+		Summary:  response_dtos.CrawlSummary{PagesCrawled: 3},
+	}
+
+	logger := log_utils.InitConsoleLogger()
+	jobConfig := &configurations.JobConfigurations{WorkerCount: 1, QueueSize: 10, RetentionMinutes: 60}
+
+	mockService := mocks.NewMockWebAnalyzerService(ctrl)
+	mockService.EXPECT().CrawlSite(gomock.Any(), parsedURL, request).Return(crawlResult, nil)
+
+	service := NewAnalysisJobService(logger, mockService, nil, jobConfig)
+
+	submitted, err := service.SubmitCrawlJob(context.Background(), parsedURL, request)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, submitted.JobId)
+	assert.Equal(t, JobTypeCrawl, submitted.Type)
+	assert.Equal(t, parsedURL.String(), submitted.Url)
+
+	job := waitForJob(t, service, submitted.JobId)
+	assert.Equal(t, JobStatusCompleted, job.Status)
+	assert.Equal(t, crawlResult, job.CrawlResult)
+}
+
+func TestAnalysisJobServiceTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parsedURL, _ := url.Parse("http://test.test")
+	logger := log_utils.InitConsoleLogger()
+	jobConfig := &configurations.JobConfigurations{WorkerCount: 1, QueueSize: 10, RetentionMinutes: 60, TimeoutMinutes: 1}
+
+	// the analysis runs until its context is cancelled by the timeout of the job
+	mockService := mocks.NewMockWebAnalyzerService(ctrl)
+	mockService.EXPECT().AnalyzeUrl(gomock.Any(), parsedURL, request_dtos.AnalyzeOptions{}).DoAndReturn(
+		func(ctx context.Context, parsedURL *url.URL, options request_dtos.AnalyzeOptions) (*response_dtos.UrlAnalyzerResponse, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+	service := NewAnalysisJobService(logger, mockService, nil, jobConfig).(*analysisJobServiceImpl)
+	assert.Equal(t, time.Minute, service.jobTimeout)
+	service.jobTimeout = 50 * time.Millisecond
+
+	submitted, err := service.SubmitJob(context.Background(), parsedURL, request_dtos.AnalyzeOptions{})
+	assert.Nil(t, err)
+
+	job := waitForJob(t, service, submitted.JobId)
+	assert.Equal(t, JobStatusFailed, job.Status)
+	assert.Equal(t, http.StatusGatewayTimeout, job.Error.Code)
+	assert.Equal(t, "the job did not finish within 50ms: context deadline exceeded", job.Error.Message)
+}
+
+func TestAnalysisJobServiceExpiredJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := log_utils.InitConsoleLogger()
+	jobConfig := &configurations.JobConfigurations{WorkerCount: 0, QueueSize: 1, RetentionMinutes: 60}
+
+	service := NewAnalysisJobService(logger, mocks.NewMockWebAnalyzerService(ctrl), nil, jobConfig).(*analysisJobServiceImpl)
+	finishedAt := time.Now().Add(-2 * time.Hour)
+	service.jobs["expired-job"] = &analysisJob{id: "expired-job", status: JobStatusCompleted, createdAt: finishedAt, updatedAt: finishedAt}
+	service.jobs["running-job"] = &analysisJob{id: "running-job", status: JobStatusRunning, createdAt: finishedAt, updatedAt: finishedAt}
+
+	// finished jobs are removed once their retention is over, even when no new jobs are submitted
+	_, err := service.GetJob(context.Background(), "expired-job")
+	customErr, ok := err.(*custom_errors.CustomError)
+	if !ok {
+		t.Fatalf("error should be of type *CustomError, got %T: %v", err, err)
+	}
+	assert.Equal(t, http.StatusNotFound, customErr.Code)
+
+	job, err := service.GetJob(context.Background(), "running-job")
+	assert.Nil(t, err)
+	assert.Equal(t, JobStatusRunning, job.Status)
+}
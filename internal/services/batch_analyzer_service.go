@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/request_dtos"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"net/url"
+)
+
+type BatchAnalyzerService interface {
+	ValidateBatch(ctx context.Context, request request_dtos.BatchAnalyzerRequest) error
+	AnalyzeBatch(ctx context.Context, parsedURLs []*url.URL, request request_dtos.BatchAnalyzerRequest) (*response_dtos.BatchAnalyzerResponse, error)
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/custom_errors"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/request_dtos"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const batchAnalyzerServiceLogPrefix = "batch_analyzer_service_impl"
+
+type batchProgressKey struct{}
+
+// batchProgressFunc - receives the number of analyzed urls and the total number of urls in the batch
+type batchProgressFunc func(analyzed int, total int)
+
+// withBatchProgress - returns a copy of ctx which makes AnalyzeBatch report its progress to fn
+func withBatchProgress(ctx context.Context, fn batchProgressFunc) context.Context {
+	return context.WithValue(ctx, batchProgressKey{}, fn)
+}
+
+// reportBatchProgress - calls the progress function attached to ctx, if there is one
+func reportBatchProgress(ctx context.Context, analyzed int, total int) {
+	if fn, ok := ctx.Value(batchProgressKey{}).(batchProgressFunc); ok && fn != nil {
+		fn(analyzed, total)
+	}
+}
+
+type batchAnalyzerServiceImpl struct {
+	logger             log_utils.LoggerInterface
+	webAnalyzerService WebAnalyzerService
+	batchConfig        *configurations.BatchConfigurations
+	workers            chan struct{}
+}
+
+// NewBatchAnalyzerService - creates the batch service. the worker group of size batchConfig.WorkerCount
+// is shared by all the batch requests, so the concurrency is bounded globally and not per request
+func NewBatchAnalyzerService(
+	logger log_utils.LoggerInterface,
+	webAnalyzerService WebAnalyzerService,
+	batchConfig *configurations.BatchConfigurations,
+) BatchAnalyzerService {
+	return &batchAnalyzerServiceImpl{
+		logger:             logger,
+		webAnalyzerService: webAnalyzerService,
+		batchConfig:        batchConfig,
+		workers:            make(chan struct{}, batchConfig.WorkerCount),
+	}
+}
+
+// ValidateBatch - rejects batches larger than batchConfig.MaxBatchSize
+func (b *batchAnalyzerServiceImpl) ValidateBatch(ctx context.Context, request request_dtos.BatchAnalyzerRequest) error {
+	if len(request.Urls) > b.batchConfig.MaxBatchSize {
+		err := fmt.Errorf("got %v urls while the limit is %v", len(request.Urls), b.batchConfig.MaxBatchSize)
+		b.logger.ErrorWithContext(ctx, "batch size exceeds the limit", err, log_utils.SetLogFile(batchAnalyzerServiceLogPrefix))
+		return custom_errors.NewCustomError(http.StatusBadRequest, "batch size exceeds the limit", err)
+	}
+	return nil
+}
+
+// AnalyzeBatch - analyzes every url in the request with its own options and returns the result
+// or the error of each url in the request order, with a summary of the totals.
+// parsedURLs are the validated urls of request.Urls in the same order, a nil url is reported as failed to parse.
+// the number of analyzed urls is reported to the progress function attached to ctx as each url completes.
+// batches larger than batchConfig.MaxBatchSize are rejected
+func (b *batchAnalyzerServiceImpl) AnalyzeBatch(ctx context.Context, parsedURLs []*url.URL, request request_dtos.BatchAnalyzerRequest) (*response_dtos.BatchAnalyzerResponse, error) {
+	if err := b.ValidateBatch(ctx, request); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	results := make([]response_dtos.BatchAnalyzerResult, len(request.Urls))
+
+	var analyzed atomic.Int64
+	reportBatchProgress(ctx, 0, len(request.Urls))
+
+	for i, urlRequest := range request.Urls {
+		wg.Add(1)
+		b.workers <- struct{}{} // acquire worker
+
+		go func(i int, urlRequest request_dtos.UrlAnalyzerRequest) {
+			defer wg.Done()
+			defer func() { <-b.workers }() // release worker
+
+			results[i] = b.analyze(ctx, parsedURLs[i], urlRequest)
+			reportBatchProgress(ctx, int(analyzed.Add(1)), len(request.Urls))
+		}(i, urlRequest)
+	}
+
+	wg.Wait()
+
+	response := &response_dtos.BatchAnalyzerResponse{
+		Results: results,
+		Summary: summarizeBatch(results),
+	}
+	response.Summary.DurationMs = time.Since(start).Milliseconds()
+
+	b.logger.InfoWithContext(ctx, fmt.Sprintf("analyzed a batch of %v urls, %v succeeded and %v failed", response.Summary.TotalUrls, response.Summary.Succeeded, response.Summary.Failed), log_utils.SetLogFile(batchAnalyzerServiceLogPrefix))
+
+	return response, nil
+}
+
+func (b *batchAnalyzerServiceImpl) analyze(ctx context.Context, parsedURL *url.URL, urlRequest request_dtos.UrlAnalyzerRequest) response_dtos.BatchAnalyzerResult {
+	batchResult := response_dtos.BatchAnalyzerResult{Url: urlRequest.Url}
+
+	if parsedURL == nil {
+		batchResult.Error = &response_dtos.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "failed to parse url",
+		}
+		return batchResult
+	}
+
+	result, err := b.webAnalyzerService.AnalyzeUrl(ctx, parsedURL, urlRequest.Options)
+	if err != nil {
+		b.logger.ErrorWithContext(ctx, fmt.Sprintf("failed to analyze url %v", urlRequest.Url), err, log_utils.SetLogFile(batchAnalyzerServiceLogPrefix))
+		batchResult.Error = toErrorResponse(urlRequest.Url, err)
+		return batchResult
+	}
+
+	batchResult.Result = result
+	return batchResult
+}
+
+func summarizeBatch(results []response_dtos.BatchAnalyzerResult) response_dtos.BatchAnalyzerSummary {
+	summary := response_dtos.BatchAnalyzerSummary{TotalUrls: len(results)}
+
+	for _, batchResult := range results {
+		if batchResult.Result == nil {
+			summary.Failed++
+			continue
+		}
+
+		summary.Succeeded++
+		summary.InternalLinks += batchResult.Result.InternalLinks
+		summary.ExternalLinks += batchResult.Result.ExternalLinks
+		summary.InaccessibleLinks += batchResult.Result.InaccessibleLinks
+		if batchResult.Result.LoginForm {
+			summary.LoginForms++
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/custom_errors"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/request_dtos"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/DaminduDilsara/web-analyzer/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := log_utils.InitConsoleLogger()
+	batchConfig := &configurations.BatchConfigurations{MaxBatchSize: 4, WorkerCount: 2}
+
+	firstURL, _ := url.Parse("http://first.test")
+	secondURL, _ := url.Parse("http://second.test")
+	thirdURL, _ := url.Parse("http://third.test")
+
+	mockService := mocks.NewMockWebAnalyzerService(ctrl)
+	mockService.EXPECT().AnalyzeUrl(gomock.Any(), firstURL, request_dtos.AnalyzeOptions{}).Return(&response_dtos.UrlAnalyzerResponse{
+		InternalLinks:     2,
+		ExternalLinks:     3,
+		InaccessibleLinks: 1,
+		LoginForm:         true,
+	}, nil)
+	mockService.EXPECT().AnalyzeUrl(gomock.Any(), secondURL, request_dtos.AnalyzeOptions{SkipLinkCheck: true}).Return(&response_dtos.UrlAnalyzerResponse{
+		InternalLinks: 1,
+		ExternalLinks: 0,
+	}, nil)
+	mockService.EXPECT().AnalyzeUrl(gomock.Any(), thirdURL, request_dtos.AnalyzeOptions{}).Return(nil, custom_errors.NewCustomError(http.StatusNotFound, "unexpected HTTP status code", nil))
+
+	service := NewBatchAnalyzerService(logger, mockService, batchConfig)
+
+	var progress []int
+	var mu sync.Mutex
+	ctx := withBatchProgress(context.Background(), func(analyzed int, total int) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, 4, total)
+		progress = append(progress, analyzed)
+	})
+
+	// the invalid url is given as nil by the controller
+	result, err := service.AnalyzeBatch(ctx, []*url.URL{firstURL, secondURL, thirdURL, nil}, request_dtos.BatchAnalyzerRequest{
+		Urls: []request_dtos.UrlAnalyzerRequest{
+			{Url: "http://first.test"},
+			{Url: "http://second.test", Options: request_dtos.AnalyzeOptions{SkipLinkCheck: true}},
+			{Url: "http://third.test"},
+			{Url: "bad_url"},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, result.Results, 4)
+	assert.ElementsMatch(t, []int{0, 1, 2, 3, 4}, progress)
+
+	// results are returned in the request order
+	assert.Equal(t, "http://first.test", result.Results[0].Url)
+	assert.NotNil(t, result.Results[0].Result)
+	assert.Equal(t, "http://second.test", result.Results[1].Url)
+	assert.NotNil(t, result.Results[1].Result)
+	assert.Equal(t, "http://third.test", result.Results[2].Url)
+	assert.Nil(t, result.Results[2].Result)
+	assert.Equal(t, &response_dtos.ErrorResponse{Code: http.StatusNotFound, Message: "unexpected HTTP status code: <nil>"}, result.Results[2].Error)
+	assert.Equal(t, "bad_url", result.Results[3].Url)
+	assert.Equal(t, &response_dtos.ErrorResponse{Code: http.StatusBadRequest, Message: "failed to parse url"}, result.Results[3].Error)
+
+	assert.Equal(t, 4, result.Summary.TotalUrls)
+	assert.Equal(t, 2, result.Summary.Succeeded)
+	assert.Equal(t, 2, result.Summary.Failed)
+	assert.Equal(t, 3, result.Summary.InternalLinks)
+	assert.Equal(t, 3, result.Summary.ExternalLinks)
+	assert.Equal(t, 1, result.Summary.InaccessibleLinks)
+	assert.Equal(t, 1, result.Summary.LoginForms)
+}
+
+func TestAnalyzeBatchSizeLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := log_utils.InitConsoleLogger()
+	batchConfig := &configurations.BatchConfigurations{MaxBatchSize: 1, WorkerCount: 1}
+
+	service := NewBatchAnalyzerService(logger, mocks.NewMockWebAnalyzerService(ctrl), batchConfig)
+
+	firstURL, _ := url.Parse("http://first.test")
+	secondURL, _ := url.Parse("http://second.test")
+
+	result, err := service.AnalyzeBatch(context.Background(), []*url.URL{firstURL, secondURL}, request_dtos.BatchAnalyzerRequest{
+		Urls: []request_dtos.UrlAnalyzerRequest{{Url: "http://first.test"}, {Url: "http://second.test"}},
+	})
+
+	assert.Nil(t, result)
+	customErr, ok := err.(*custom_errors.CustomError)
+	if !ok {
+		t.Fatalf("error should be of type *CustomError, got %T: %v", err, err)
+	}
+	assert.Equal(t, http.StatusBadRequest, customErr.Code)
+	assert.Equal(t, "batch size exceeds the limit", customErr.Message)
+}
@@ -2,10 +2,12 @@ package services
 
 import (
 	"context"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/request_dtos"
 	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
 	"net/url"
 )
 
 type WebAnalyzerService interface {
-	AnalyzeUrl(ctx context.Context, parsedURL *url.URL) (*response_dtos.UrlAnalyzerResponse, error)
+	AnalyzeUrl(ctx context.Context, parsedURL *url.URL, options request_dtos.AnalyzeOptions) (*response_dtos.UrlAnalyzerResponse, error)
+	CrawlSite(ctx context.Context, parsedURL *url.URL, request request_dtos.CrawlRequest) (*response_dtos.CrawlResponse, error)
 }
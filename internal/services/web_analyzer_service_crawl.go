@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/request_dtos"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/DaminduDilsara/web-analyzer/internal/web_analyzer_utils"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type crawlTarget struct {
+	url   *url.URL
+	depth int
+}
+
+type crawledPage struct {
+	result   response_dtos.CrawlPageResult
+	links    []string
+	finalURL *url.URL
+	err      error
+}
+
+// CrawlSite - crawls the site starting from the given url and analyzes every page with the same
+// detectors used by AnalyzeUrl. the internal links of each page are followed level by level until
+// - the depth reaches request.MaxDepth
+// - request.MaxPages pages are analyzed
+// only the links in the scope of the final url of the start page under webAnalyzerConfig.LinkScope and with a path
+// in request.PathPrefix are followed, and the links of pages which redirected out of that scope are not.
+// pages which redirected to a page already crawled are not reported again and do not count towards request.MaxPages.
+// zero values in the request fall back to the crawl configurations, which also act as upper limits
+func (w *webAnalyzerServiceImpl) CrawlSite(ctx context.Context, parsedURL *url.URL, request request_dtos.CrawlRequest) (*response_dtos.CrawlResponse, error) {
+	start := time.Now()
+
+	maxDepth := limitCrawlValue(request.MaxDepth, w.crawlConfig.MaxDepth)
+	maxPages := limitCrawlValue(request.MaxPages, w.crawlConfig.MaxPages)
+
+	response := &response_dtos.CrawlResponse{
+		StartUrl:   parsedURL.String(),
+		MaxDepth:   maxDepth,
+		MaxPages:   maxPages,
+		PathPrefix: request.PathPrefix,
+	}
+
+	startURL := *parsedURL
+	startURL.Fragment = ""
+	visited := map[string]bool{startURL.String(): true} // urls queued for the crawl
+	crawled := make(map[string]bool)                    // final urls of the crawled pages
+	frontier := []crawlTarget{{url: &startURL, depth: 0}}
+	var siteURL *url.URL
+
+	for depth := 0; len(frontier) > 0 && depth <= maxDepth; depth++ {
+		if remaining := maxPages - len(response.Pages); len(frontier) > remaining {
+			frontier = frontier[:remaining]
+			response.Summary.PageBudgetReached = true
+			if remaining == 0 {
+				break
+			}
+		}
+
+		crawledPages := w.crawlLevel(ctx, frontier, request.Options)
+
+		if depth == 0 {
+			if crawledPages[0].err != nil { // nothing to crawl when the start page fails
+				w.logger.ErrorWithContext(ctx, "failed to analyze the start page of the crawl", crawledPages[0].err, log_utils.SetLogFile(webAnalyzerServiceLogPrefix))
+				return nil, crawledPages[0].err
+			}
+			siteURL = crawledPages[0].finalURL // the start url may redirect, e.g. from http to https or to the www host
+		}
+
+		var nextFrontier []crawlTarget
+		for _, page := range crawledPages {
+			if page.finalURL != nil {
+				finalURL := *page.finalURL
+				finalURL.Fragment = ""
+				if crawled[finalURL.String()] {
+					w.logger.InfoWithContext(ctx, fmt.Sprintf("skipping %v which redirected to the already crawled page %v", page.result.Url, finalURL.String()), log_utils.SetLogFile(webAnalyzerServiceLogPrefix))
+					continue
+				}
+				crawled[finalURL.String()] = true
+				visited[finalURL.String()] = true
+			}
+
+			response.Pages = append(response.Pages, page.result)
+
+			if page.finalURL == nil || depth == maxDepth { // failed pages and redirect loops have no links to follow
+				continue
+			}
+			if !web_analyzer_utils.IsInScope(w.linkScope, siteURL, page.finalURL) {
+				continue
+			}
+
+			for _, link := range page.links {
+				linkURL, ok := resolveCrawlLink(page.finalURL, link, siteURL, w.linkScope, request.PathPrefix)
+				if !ok || visited[linkURL.String()] {
+					continue
+				}
+				visited[linkURL.String()] = true
+				nextFrontier = append(nextFrontier, crawlTarget{url: linkURL, depth: depth + 1})
+			}
+		}
+
+		frontier = nextFrontier
+	}
+
+	summarizeCrawl(response)
+	response.Summary.DurationMs = time.Since(start).Milliseconds()
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("crawled %v pages of %v, %v failed", response.Summary.PagesCrawled, parsedURL, response.Summary.PagesFailed), log_utils.SetLogFile(webAnalyzerServiceLogPrefix))
+
+	return response, nil
+}
+
+// crawlLevel - analyzes the pages of a crawl level using a worker group of size crawlConfig.WorkerCount.
+// results are returned in the same order as the targets
+func (w *webAnalyzerServiceImpl) crawlLevel(ctx context.Context, targets []crawlTarget, options request_dtos.AnalyzeOptions) []crawledPage {
+	workers := make(chan struct{}, w.crawlConfig.WorkerCount)
+	var wg sync.WaitGroup
+	crawledPages := make([]crawledPage, len(targets))
+
+	for i, target := range targets {
+		wg.Add(1)
+		workers <- struct{}{} // acquire worker
+
+		go func(i int, target crawlTarget) {
+			defer wg.Done()
+			defer func() { <-workers }() // release worker
+
+			page := crawledPage{
+				result: response_dtos.CrawlPageResult{Url: target.url.String(), Depth: target.depth},
+			}
+
+			result, links, finalURL, err := w.analyzePage(ctx, target.url, options)
+			if err != nil {
+				page.err = err
+				page.result.Error = toErrorResponse(target.url.String(), err)
+			} else {
+				page.result.Result = result
+				page.links = links
+				page.finalURL = finalURL
+			}
+			crawledPages[i] = page
+		}(i, target)
+	}
+
+	wg.Wait()
+
+	return crawledPages
+}
+
+// resolveCrawlLink - resolves the link against the page it was found in and checks if it is in the crawl scope,
+// which is the scope of the site url under the link scope policy (see web_analyzer_utils.IsInScope) and the paths
+// in pathPrefix (see isInPathPrefix). fragments are dropped so the same page is not crawled twice
+func resolveCrawlLink(pageURL *url.URL, link string, siteURL *url.URL, linkScope string, pathPrefix string) (*url.URL, bool) {
+	ref, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return nil, false
+	}
+
+	linkURL := pageURL.ResolveReference(ref)
+	linkURL.Fragment = ""
+
+	if linkURL.Scheme != "http" && linkURL.Scheme != "https" {
+		return nil, false
+	}
+	if !web_analyzer_utils.IsInScope(linkScope, siteURL, linkURL) {
+		return nil, false
+	}
+	if !isInPathPrefix(linkURL.Path, pathPrefix) {
+		return nil, false
+	}
+
+	return linkURL, true
+}
+
+// isInPathPrefix - checks if the path is in the path prefix by whole segments, so /docs matches /docs and /docs/a
+// but not /docs-old. an empty prefix matches every path
+func isInPathPrefix(path string, pathPrefix string) bool {
+	pathPrefix = strings.TrimSuffix(pathPrefix, "/")
+	if pathPrefix == "" {
+		return true
+	}
+	return path == pathPrefix || strings.HasPrefix(path, pathPrefix+"/")
+}
+
+// limitCrawlValue - returns the requested value, or the configured value when the requested value
+// is not given or exceeds it
+func limitCrawlValue(requested int, configured int) int {
+	if requested <= 0 || requested > configured {
+		return configured
+	}
+	return requested
+}
+
+func summarizeCrawl(response *response_dtos.CrawlResponse) {
+	summary := &response.Summary
+	summary.Headings = make(map[string]int)
+	summary.HTMLVersions = make(map[string]int)
+
+	for _, page := range response.Pages {
+		summary.PagesCrawled++
+		if page.Depth > summary.DeepestLevel {
+			summary.DeepestLevel = page.Depth
+		}
+
+		if page.Result == nil {
+			summary.PagesFailed++
+			continue
+		}
+
+		summary.InternalLinks += page.Result.InternalLinks
+		summary.ExternalLinks += page.Result.ExternalLinks
+		summary.InaccessibleLinks += page.Result.InaccessibleLinks
+		summary.MalformedLinks += page.Result.MalformedLinks
+		summary.RestrictedLinks += page.Result.RestrictedLinks
+		summary.RateLimitedLinks += page.Result.RateLimitedLinks
+		if page.Result.LoginForm {
+			summary.PagesWithLoginForm++
+		}
+		for heading, count := range page.Result.Headings {
+			summary.Headings[heading] += count
+		}
+		summary.HTMLVersions[page.Result.HTMLVersion]++
+	}
+}
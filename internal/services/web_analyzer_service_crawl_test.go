@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/custom_errors"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/request_dtos"
+	"github.com/DaminduDilsara/web-analyzer/internal/web_analyzer_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// newCrawlTestSite creates a site with the page tree
+//
+//	/ -> /docs/a, /docs/b, /blog, external link
+//	/docs/a -> /docs/a/deep, / (already visited)
+//	/docs/b -> /missing
+func newCrawlTestSite() *httptest.Server {
+	pages := map[string]string{
+		"/":            `<html><head><title>Home</title></head><body><h1>Home</h1><a href="/docs/a">A</a><a href="docs/b#section">B</a><a href="/blog">Blog</a><a href="https://external.test/">External</a></body></html>`,
+		"/docs/a":      `<html><head><title>A</title></head><body><h1>A</h1><a href="/docs/a/deep">Deep</a><a href="/">Home</a></body></html>`,
+		"/docs/b":      `<html><head><title>B</title></head><body><h2>B</h2><a href="/missing">Missing</a></body></html>`,
+		"/docs/a/deep": `<html><head><title>Deep</title></head><body><form><input type="password"></form></body></html>`,
+		"/blog":        `<html><head><title>Blog</title></head><body></body></html>`,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, page)
+	}))
+}
+
+func TestCrawlSite(t *testing.T) {
+	srv := newCrawlTestSite()
+	defer srv.Close()
+
+	logger := log_utils.InitConsoleLogger()
+	webAnalyzerUtils := web_analyzer_utils.NewWebAnalyzerUtils(logger, &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1})
+	crawlConfig := &configurations.CrawlConfigurations{MaxDepth: 3, MaxPages: 10, WorkerCount: 2}
+	service := NewWebAnalyzerServiceWithClient(logger, webAnalyzerUtils, nil, crawlConfig, &http.Client{Timeout: 5 * time.Second})
+
+	startURL, _ := url.Parse(srv.URL + "/")
+	options := request_dtos.AnalyzeOptions{SkipLinkCheck: true}
+
+	tests := []struct {
+		name               string
+		request            request_dtos.CrawlRequest
+		expectedPages      []string
+		expectedFailed     int
+		expectedBudget     bool
+		expectedDeepest    int
+		expectedLoginForms int
+		expectedH1Headings int
+		expectedMaxDepth   int
+		expectedMaxPages   int
+		expectedPathPrefix string
+	}{
+		{
+			name:               "Full Crawl",
+			request:            request_dtos.CrawlRequest{Options: options},
+			expectedPages:      []string{"/", "/docs/a", "/docs/b", "/blog", "/docs/a/deep", "/missing"},
+			expectedFailed:     1,
+			expectedDeepest:    2,
+			expectedLoginForms: 1,
+			expectedH1Headings: 2,
+			expectedMaxDepth:   3,
+			expectedMaxPages:   10,
+		},
+		{
+			name:               "Depth Limit",
+			request:            request_dtos.CrawlRequest{MaxDepth: 1, Options: options},
+			expectedPages:      []string{"/", "/docs/a", "/docs/b", "/blog"},
+			expectedDeepest:    1,
+			expectedH1Headings: 2,
+			expectedMaxDepth:   1,
+			expectedMaxPages:   10,
+		},
+		{
+			name:               "Page Budget",
+			request:            request_dtos.CrawlRequest{MaxPages: 2, Options: options},
+			expectedPages:      []string{"/", "/docs/a"},
+			expectedBudget:     true,
+			expectedDeepest:    1,
+			expectedH1Headings: 2,
+			expectedMaxDepth:   3,
+			expectedMaxPages:   2,
+		},
+		{
+			name:               "Path Prefix Scope",
+			request:            request_dtos.CrawlRequest{PathPrefix: "/docs/a", Options: options},
+			expectedPages:      []string{"/", "/docs/a", "/docs/a/deep"},
+			expectedDeepest:    2,
+			expectedLoginForms: 1,
+			expectedH1Headings: 2,
+			expectedMaxDepth:   3,
+			expectedMaxPages:   10,
+			expectedPathPrefix: "/docs/a",
+		},
+		{
+			name:               "Limits Above Configuration",
+			request:            request_dtos.CrawlRequest{MaxDepth: 100, MaxPages: 100, Options: options},
+			expectedPages:      []string{"/", "/docs/a", "/docs/b", "/blog", "/docs/a/deep", "/missing"},
+			expectedFailed:     1,
+			expectedDeepest:    2,
+			expectedLoginForms: 1,
+			expectedH1Headings: 2,
+			expectedMaxDepth:   3,
+			expectedMaxPages:   10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.CrawlSite(context.Background(), startURL, tt.request)
+			assert.Nil(t, err)
+
+			var crawledPages []string
+			for _, page := range result.Pages {
+				crawledPages = append(crawledPages, page.Url[len(srv.URL):])
+			}
+			assert.Equal(t, tt.expectedPages, crawledPages)
+
+			assert.Equal(t, tt.expectedMaxDepth, result.MaxDepth)
+			assert.Equal(t, tt.expectedMaxPages, result.MaxPages)
+			assert.Equal(t, tt.expectedPathPrefix, result.PathPrefix)
+			assert.Equal(t, len(tt.expectedPages), result.Summary.PagesCrawled)
+			assert.Equal(t, tt.expectedFailed, result.Summary.PagesFailed)
+			assert.Equal(t, tt.expectedBudget, result.Summary.PageBudgetReached)
+			assert.Equal(t, tt.expectedDeepest, result.Summary.DeepestLevel)
+			assert.Equal(t, tt.expectedLoginForms, result.Summary.PagesWithLoginForm)
+			assert.Equal(t, tt.expectedH1Headings, result.Summary.Headings["h1"])
+		})
+	}
+}
+
+func TestCrawlSiteStartPageError(t *testing.T) {
+	srv := newCrawlTestSite()
+	defer srv.Close()
+
+	logger := log_utils.InitConsoleLogger()
+	webAnalyzerUtils := web_analyzer_utils.NewWebAnalyzerUtils(logger, &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1})
+	crawlConfig := &configurations.CrawlConfigurations{MaxDepth: 3, MaxPages: 10, WorkerCount: 2}
+	service := NewWebAnalyzerServiceWithClient(logger, webAnalyzerUtils, nil, crawlConfig, &http.Client{Timeout: 5 * time.Second})
+
+	startURL, _ := url.Parse(srv.URL + "/missing")
+
+	result, err := service.CrawlSite(context.Background(), startURL, request_dtos.CrawlRequest{})
+
+	assert.Nil(t, result)
+	customErr, ok := err.(*custom_errors.CustomError)
+	if !ok {
+		t.Fatalf("error should be of type *CustomError, got %T: %v", err, err)
+	}
+	assert.Equal(t, http.StatusNotFound, customErr.Code)
+	assert.Equal(t, "unexpected HTTP status code", customErr.Message)
+}
+
+func TestCrawlSiteStartRedirect(t *testing.T) {
+	// the site is served on localhost and the start url on 127.0.0.1 redirects to it,
+	// like a redirect from the apex domain to the www host
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, port, _ := net.SplitHostPort(r.Host)
+		if host != "localhost" {
+			http.Redirect(w, r, "http://localhost:"+port+r.URL.Path, http.StatusMovedPermanently)
+			return
+		}
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprintf(w, `<html><body><a href="/docs">Docs</a><a href="http://127.0.0.1:%v/other">Start Host</a></body></html>`, port)
+		case "/docs":
+			fmt.Fprint(w, `<html><body><a href="/">Home</a></body></html>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	logger := log_utils.InitConsoleLogger()
+	webAnalyzerUtils := web_analyzer_utils.NewWebAnalyzerUtils(logger, &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1})
+	crawlConfig := &configurations.CrawlConfigurations{MaxDepth: 3, MaxPages: 10, WorkerCount: 2}
+	service := NewWebAnalyzerServiceWithClient(logger, webAnalyzerUtils, nil, crawlConfig, &http.Client{Timeout: 5 * time.Second})
+
+	startURL, _ := url.Parse(srv.URL + "/")
+
+	result, err := service.CrawlSite(context.Background(), startURL, request_dtos.CrawlRequest{Options: request_dtos.AnalyzeOptions{SkipLinkCheck: true}})
+	assert.Nil(t, err)
+
+	// the links are followed in the scope of the final url of the start page, so the links to the
+	// host of the start url are not, and the final url is not crawled again when it is linked
+	var crawledPages []string
+	for _, page := range result.Pages {
+		crawledPages = append(crawledPages, page.Url)
+	}
+	siteURL := "http://localhost:" + startURL.Port()
+	assert.Equal(t, []string{srv.URL + "/", siteURL + "/docs"}, crawledPages)
+	assert.Equal(t, 0, result.Summary.PagesFailed)
+}
+
+func TestCrawlSiteRedirectedLinks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, `<html><body><a href="/old">Old</a><a href="/older">Older</a><a href="/new">New</a></body></html>`)
+		case "/old", "/older":
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+		case "/new":
+			fmt.Fprint(w, `<html><body><a href="/">Home</a></body></html>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	logger := log_utils.InitConsoleLogger()
+	webAnalyzerUtils := web_analyzer_utils.NewWebAnalyzerUtils(logger, &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1})
+	crawlConfig := &configurations.CrawlConfigurations{MaxDepth: 3, MaxPages: 10, WorkerCount: 1}
+	service := NewWebAnalyzerServiceWithClient(logger, webAnalyzerUtils, nil, crawlConfig, &http.Client{Timeout: 5 * time.Second})
+
+	startURL, _ := url.Parse(srv.URL + "/")
+
+	result, err := service.CrawlSite(context.Background(), startURL, request_dtos.CrawlRequest{Options: request_dtos.AnalyzeOptions{SkipLinkCheck: true}})
+	assert.Nil(t, err)
+
+	// the three links lead to the same page, which is reported once
+	var crawledPages []string
+	for _, page := range result.Pages {
+		crawledPages = append(crawledPages, page.Url[len(srv.URL):])
+	}
+	assert.Equal(t, []string{"/", "/old"}, crawledPages)
+	assert.Equal(t, 2, result.Summary.PagesCrawled)
+}
+
+func TestCrawlSiteRedirectLoop(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, `<html><body><a href="/a">A</a></body></html>`)
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusMovedPermanently)
+		case "/b":
+			w.Header().Set("Location", "/a")
+			w.WriteHeader(http.StatusFound)
+			fmt.Fprint(w, `<html><body><a href="/other">Other</a></body></html>`)
+		default:
+			fmt.Fprint(w, `<html><body></body></html>`)
+		}
+	}))
+	defer srv.Close()
+
+	logger := log_utils.InitConsoleLogger()
+	webAnalyzerUtils := web_analyzer_utils.NewWebAnalyzerUtils(logger, &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1})
+	crawlConfig := &configurations.CrawlConfigurations{MaxDepth: 3, MaxPages: 10, WorkerCount: 1}
+	service := NewWebAnalyzerServiceWithClient(logger, webAnalyzerUtils, nil, crawlConfig, &http.Client{Timeout: 5 * time.Second})
+
+	startURL, _ := url.Parse(srv.URL + "/")
+
+	result, err := service.CrawlSite(context.Background(), startURL, request_dtos.CrawlRequest{Options: request_dtos.AnalyzeOptions{SkipLinkCheck: true}})
+	assert.Nil(t, err)
+
+	// the looping page is reported with its redirect chain, and the links of the redirect response are not followed
+	assert.Equal(t, 2, len(result.Pages))
+	loopPage := result.Pages[1]
+	assert.Equal(t, srv.URL+"/a", loopPage.Url)
+	assert.Nil(t, loopPage.Error)
+	assert.Equal(t, http.StatusFound, loopPage.Result.HTTPResponse.StatusCode)
+	var codes []string
+	for _, issue := range loopPage.Result.Redirects.Issues {
+		codes = append(codes, issue.Code)
+	}
+	assert.Contains(t, codes, "redirect_loop")
+}
+
+func TestIsInPathPrefix(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		pathPrefix string
+		expected   bool
+	}{
+		{name: "No Prefix", path: "/blog", pathPrefix: "", expected: true},
+		{name: "Same Path", path: "/docs", pathPrefix: "/docs", expected: true},
+		{name: "Sub Path", path: "/docs/a", pathPrefix: "/docs", expected: true},
+		{name: "Prefix With Trailing Slash", path: "/docs/a", pathPrefix: "/docs/", expected: true},
+		{name: "Sibling With Same Start", path: "/docs-old", pathPrefix: "/docs", expected: false},
+		{name: "Longer Segment", path: "/docsearch", pathPrefix: "/docs", expected: false},
+		{name: "Parent Path", path: "/", pathPrefix: "/docs", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isInPathPrefix(tt.path, tt.pathPrefix))
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/custom_errors"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/DaminduDilsara/web-analyzer/internal/web_analyzer_utils"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// content encodings requested from the server. the encoding is requested explicitly so the transport
+// does not decompress the body transparently and the transferred size can be measured
+const acceptedContentEncodings = "gzip, deflate"
+
+// maxRedirects - number of redirects followed before the fetch is stopped
+const maxRedirects = 10
+
+var errTooManyRedirects = errors.New("too many redirects")
+
+// unexpectedStatusError - error of a fetch answered with a non 2xx status code
+type unexpectedStatusError struct {
+	statusCode int
+	header     http.Header
+}
+
+func (e *unexpectedStatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status code: %d %s", e.statusCode, http.StatusText(e.statusCode))
+}
+
+type fetchedPage struct {
+	body         []byte
+	response     *http.Response
+	metadata     response_dtos.HTTPResponseMetadata
+	redirects    []response_dtos.RedirectHop
+	finalURL     *url.URL
+	redirectLoop bool
+}
+
+// fetchPage - fetches the page and returns its decoded body along with the response metadata and the redirect chain.
+// failed attempts are retried as the retry policy allows. the wait before the next attempt is the Retry-After
+// of the response when it has one, and the backoff of the policy otherwise
+func (w *webAnalyzerServiceImpl) fetchPage(ctx context.Context, parsedURL *url.URL) (*fetchedPage, error) {
+	for attempt := 1; ; attempt++ {
+		page, err := w.fetchPageAttempt(ctx, parsedURL)
+		if err == nil {
+			return page, nil
+		}
+
+		// the status code and the header are only set when the server answered with a non 2xx status code
+		statusCode, header, cause := 0, http.Header{}, err
+		var customErr *custom_errors.CustomError
+		if errors.As(err, &customErr) {
+			cause = customErr.Err
+		}
+		var statusErr *unexpectedStatusError
+		if errors.As(cause, &statusErr) {
+			statusCode, header, cause = statusErr.statusCode, statusErr.header, nil
+		}
+
+		if ctx.Err() != nil || !w.retryPolicy.ShouldRetry(attempt, statusCode, cause) {
+			return nil, err
+		}
+
+		delay := w.retryPolicy.Delay(attempt, header)
+		w.logger.InfoWithContext(ctx, fmt.Sprintf("attempt %v of %v to fetch %v failed, retrying in %v", attempt, w.retryPolicy.MaxAttempts(), parsedURL, delay), log_utils.SetLogFile(webAnalyzerServiceLogPrefix))
+		if web_analyzer_utils.SleepContext(ctx, delay) != nil {
+			return nil, err
+		}
+	}
+}
+
+// fetchPageAttempt - sends a single request for the page and reads its response.
+// the timing of each phase of the request is captured with httptrace. when the request is redirected
+// the phases of all the requests are added up. when a redirect leads back to a url of the chain the fetch
+// stops there and the page is returned as a redirect loop, with the metadata of the last redirect response and
+// without a body or final url, so the loop is reported by the redirect analysis instead of failing the fetch
+func (w *webAnalyzerServiceImpl) fetchPageAttempt(ctx context.Context, parsedURL *url.URL) (*fetchedPage, error) {
+	timer := &requestTimer{}
+
+	// each fetch records its own redirect chain, so the check is set on a copy of the client
+	var redirects []response_dtos.RedirectHop
+	var loopURL *url.URL
+	client := *w.httpClient
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		hop := response_dtos.RedirectHop{
+			Url:        via[len(via)-1].URL.String(),
+			StatusCode: req.Response.StatusCode,
+			Location:   req.Response.Header.Get("Location"),
+		}
+		// the transport does not verify certificates and only the certificate of the page is analyzed,
+		// so the certificate of each hop is verified here
+		if err := web_analyzer_utils.VerifyCertificate(req.Response.TLS, via[len(via)-1].URL.Hostname()); err != nil {
+			hop.CertificateError = err.Error()
+		}
+		redirects = append(redirects, hop)
+		for _, previous := range via {
+			if previous.URL.String() == req.URL.String() {
+				loopURL = req.URL
+				return http.ErrUseLastResponse
+			}
+		}
+		if len(via) >= maxRedirects {
+			return errTooManyRedirects
+		}
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, timer.clientTrace()), http.MethodGet, parsedURL.String(), nil)
+	if err != nil {
+		w.logger.ErrorWithContext(ctx, "Unable to create the request to the url", err, log_utils.SetLogFile(webAnalyzerServiceLogPrefix))
+		return nil, custom_errors.NewCustomError(http.StatusInternalServerError, "unable to fetch data from the given url", err)
+	}
+	req.Header.Set("Accept-Encoding", acceptedContentEncodings)
+
+	timer.start = time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		w.logger.ErrorWithContext(ctx, "Unable to fetch data from the url", err, log_utils.SetLogFile(webAnalyzerServiceLogPrefix))
+		if errors.Is(err, errTooManyRedirects) {
+			return nil, custom_errors.NewCustomError(http.StatusBadGateway, fmt.Sprintf("the url redirected more than %v times", maxRedirects), err)
+		}
+		if _, ok := err.(*url.Error); ok {
+			if strings.Contains(err.Error(), "no such host") {
+				return nil, custom_errors.NewCustomError(http.StatusNotFound, "server not found for the given url or domain does not exist", err)
+			}
+			return nil, custom_errors.NewCustomError(http.StatusBadGateway, "failed to connect to the given server", err)
+		}
+		return nil, custom_errors.NewCustomError(http.StatusInternalServerError, "unable to fetch data from the given url", err)
+	}
+	defer resp.Body.Close()
+
+	if loopURL != nil {
+		timer.end = time.Now()
+		w.logger.InfoWithContext(ctx, fmt.Sprintf("the redirect chain of %v loops back to %v", parsedURL, loopURL), log_utils.SetLogFile(webAnalyzerServiceLogPrefix))
+		metadata := response_dtos.HTTPResponseMetadata{
+			StatusCode: resp.StatusCode,
+			Protocol:   resp.Proto,
+			Server:     resp.Header.Get("Server"),
+			Timing:     timer.timing(),
+		}
+		return &fetchedPage{response: resp, metadata: metadata, redirects: redirects, redirectLoop: true}, nil
+	}
+
+	if (resp.StatusCode < http.StatusOK) || (resp.StatusCode >= http.StatusMultipleChoices) {
+		err = &unexpectedStatusError{statusCode: resp.StatusCode, header: resp.Header}
+		w.logger.ErrorWithContext(ctx, "unexpected HTTP status code", err, log_utils.SetLogFile(webAnalyzerServiceLogPrefix))
+		return nil, custom_errors.NewCustomError(resp.StatusCode, "unexpected HTTP status code", err)
+	}
+
+	rawBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		w.logger.ErrorWithContext(ctx, "response cannot parse to html", err, log_utils.SetLogFile(webAnalyzerServiceLogPrefix))
+		return nil, custom_errors.NewCustomError(http.StatusInternalServerError, "response cannot parse to html", err)
+	}
+	timer.end = time.Now()
+
+	contentEncoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	body, err := decodeBody(rawBody, contentEncoding)
+	if err != nil {
+		w.logger.ErrorWithContext(ctx, "response body cannot be decoded", err, log_utils.SetLogFile(webAnalyzerServiceLogPrefix))
+		return nil, custom_errors.NewCustomError(http.StatusBadGateway, "response body cannot be decoded", err)
+	}
+
+	metadata := response_dtos.HTTPResponseMetadata{
+		StatusCode:       resp.StatusCode,
+		Protocol:         resp.Proto,
+		ContentEncoding:  contentEncoding,
+		TransferredBytes: int64(len(rawBody)),
+		DecodedBytes:     int64(len(body)),
+		Server:           resp.Header.Get("Server"),
+		Timing:           timer.timing(),
+	}
+	if mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
+		metadata.ContentType = mediaType
+		metadata.Charset = params["charset"]
+	}
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("fetched %v bytes (%v bytes decoded) from %v in %vms", metadata.TransferredBytes, metadata.DecodedBytes, parsedURL, metadata.Timing.TotalMs), log_utils.SetLogFile(webAnalyzerServiceLogPrefix))
+
+	finalURL := parsedURL
+	if resp.Request != nil {
+		finalURL = resp.Request.URL
+	}
+
+	return &fetchedPage{body: body, response: resp, metadata: metadata, redirects: redirects, finalURL: finalURL}, nil
+}
+
+// decodeBody - decodes the body according to the content encoding of the response.
+// deflate bodies are expected to be zlib wrapped as the spec says, and are decoded as raw deflate
+// data when they are not, since some servers send raw deflate data for the deflate encoding
+func decodeBody(body []byte, contentEncoding string) ([]byte, error) {
+	var reader io.ReadCloser
+	var err error
+
+	switch contentEncoding {
+	case "", "identity":
+		return body, nil
+	case "gzip", "x-gzip":
+		reader, err = gzip.NewReader(bytes.NewReader(body))
+	case "deflate":
+		reader, err = zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			reader, err = flate.NewReader(bytes.NewReader(body)), nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", contentEncoding)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// requestTimer - collects the time spent in each phase of a request from the httptrace callbacks.
+// the callbacks can run concurrently, e.g. when dialing multiple addresses, so the timer is locked
+type requestTimer struct {
+	mu           sync.Mutex
+	start        time.Time
+	end          time.Time
+	requestStart time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	firstByte    time.Time
+	dns          time.Duration
+	connect      time.Duration
+	tls          time.Duration
+}
+
+func (r *requestTimer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		// called at the start of every request of a redirect chain
+		GetConn: func(string) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.requestStart = time.Now()
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.dns += time.Since(r.dnsStart)
+		},
+		ConnectStart: func(string, string) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.connectStart = time.Now()
+		},
+		ConnectDone: func(string, string, error) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.connect += time.Since(r.connectStart)
+		},
+		TLSHandshakeStart: func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.tls += time.Since(r.tlsStart)
+		},
+		GotFirstResponseByte: func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.firstByte = time.Now()
+		},
+	}
+}
+
+// timing - returns the durations of the phases. the time to first byte is measured from the start of
+// the last request and the download time from its first byte when the request is redirected, while the
+// total time covers the whole redirect chain
+func (r *requestTimer) timing() response_dtos.ResponseTiming {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	timing := response_dtos.ResponseTiming{
+		DNSMs:     r.dns.Milliseconds(),
+		ConnectMs: r.connect.Milliseconds(),
+		TLSMs:     r.tls.Milliseconds(),
+		TotalMs:   r.end.Sub(r.start).Milliseconds(),
+	}
+	if !r.firstByte.IsZero() {
+		timing.TTFBMs = r.firstByte.Sub(r.requestStart).Milliseconds()
+		timing.DownloadMs = r.end.Sub(r.firstByte).Milliseconds()
+	}
+	return timing
+}
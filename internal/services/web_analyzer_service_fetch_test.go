@@ -0,0 +1,365 @@
+package services
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/custom_errors"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/DaminduDilsara/web-analyzer/internal/web_analyzer_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchPage(t *testing.T) {
+	page := `<!DOCTYPE html><html><head><title>Fetch</title></head><body>` + string(bytes.Repeat([]byte("<p>text</p>"), 100)) + `</body></html>`
+
+	var gzipped bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipped)
+	gzipWriter.Write([]byte(page))
+	gzipWriter.Close()
+
+	var zlibWrapped bytes.Buffer
+	zlibWriter := zlib.NewWriter(&zlibWrapped)
+	zlibWriter.Write([]byte(page))
+	zlibWriter.Close()
+
+	var rawDeflate bytes.Buffer
+	flateWriter, _ := flate.NewWriter(&rawDeflate, flate.DefaultCompression)
+	flateWriter.Write([]byte(page))
+	flateWriter.Close()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "test-server")
+		switch r.URL.Path {
+		case "/plain":
+			w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+			w.Write([]byte(page))
+		case "/gzip":
+			assert.Equal(t, acceptedContentEncodings, r.Header.Get("Accept-Encoding"))
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(gzipped.Bytes())
+		case "/deflate":
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Content-Encoding", "deflate")
+			w.Write(zlibWrapped.Bytes())
+		case "/raw-deflate":
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Content-Encoding", "deflate")
+			w.Write(rawDeflate.Bytes())
+		case "/moved":
+			http.Redirect(w, r, "/found", http.StatusMovedPermanently)
+		case "/found":
+			http.Redirect(w, r, server.URL+"/plain", http.StatusFound)
+		case "/chain":
+			hop, _ := strconv.Atoi(r.URL.Query().Get("hop"))
+			http.Redirect(w, r, fmt.Sprintf("/chain?hop=%d", hop+1), http.StatusFound)
+		case "/brotli":
+			w.Header().Set("Content-Encoding", "br")
+			w.Write([]byte("not brotli"))
+		}
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		name                string
+		path                string
+		expectContentType   string
+		expectCharset       string
+		expectEncoding      string
+		expectTransferred   int64
+		expectRedirects     []response_dtos.RedirectHop
+		expectFinalPath     string
+		expectErrorCode     int
+		expectErrorResponse string
+	}{
+		{
+			name:              "Plain response",
+			path:              "/plain",
+			expectFinalPath:   "/plain",
+			expectContentType: "text/html",
+			expectCharset:     "UTF-8",
+			expectTransferred: int64(len(page)),
+		},
+		{
+			name:              "Gzip encoded response",
+			path:              "/gzip",
+			expectFinalPath:   "/gzip",
+			expectContentType: "text/html",
+			expectEncoding:    "gzip",
+			expectTransferred: int64(gzipped.Len()),
+		},
+		{
+			name:              "Deflate encoded response",
+			path:              "/deflate",
+			expectFinalPath:   "/deflate",
+			expectContentType: "text/html",
+			expectEncoding:    "deflate",
+			expectTransferred: int64(zlibWrapped.Len()),
+		},
+		{
+			name:              "Raw deflate encoded response",
+			path:              "/raw-deflate",
+			expectFinalPath:   "/raw-deflate",
+			expectContentType: "text/html",
+			expectEncoding:    "deflate",
+			expectTransferred: int64(rawDeflate.Len()),
+		},
+		{
+			name:              "Redirected response",
+			path:              "/moved",
+			expectFinalPath:   "/plain",
+			expectContentType: "text/html",
+			expectCharset:     "UTF-8",
+			expectTransferred: int64(len(page)),
+			expectRedirects: []response_dtos.RedirectHop{
+				{Url: server.URL + "/moved", StatusCode: http.StatusMovedPermanently, Location: "/found"},
+				{Url: server.URL + "/found", StatusCode: http.StatusFound, Location: server.URL + "/plain"},
+			},
+		},
+		{
+			name:                "Too many redirects",
+			path:                "/chain",
+			expectErrorCode:     http.StatusBadGateway,
+			expectErrorResponse: "the url redirected more than 10 times",
+		},
+		{
+			name:                "Unsupported content encoding",
+			path:                "/brotli",
+			expectErrorCode:     http.StatusBadGateway,
+			expectErrorResponse: "response body cannot be decoded",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := log_utils.InitConsoleLogger()
+			service := NewWebAnalyzerService(logger, nil, nil, nil).(*webAnalyzerServiceImpl)
+
+			pageURL, _ := url.Parse(server.URL + tc.path)
+			fetched, err := service.fetchPage(context.Background(), pageURL)
+
+			if tc.expectErrorCode != 0 {
+				assert.Nil(t, fetched)
+				ce, ok := err.(*custom_errors.CustomError)
+				if !ok {
+					t.Fatalf("error should be of type *CustomError, got %T: %v", err, err)
+				}
+				assert.Equal(t, tc.expectErrorCode, ce.Code)
+				assert.Equal(t, tc.expectErrorResponse, ce.Message)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, page, string(fetched.body))
+			assert.Equal(t, http.StatusOK, fetched.metadata.StatusCode)
+			assert.Equal(t, "HTTP/1.1", fetched.metadata.Protocol)
+			assert.Equal(t, tc.expectContentType, fetched.metadata.ContentType)
+			assert.Equal(t, tc.expectCharset, fetched.metadata.Charset)
+			assert.Equal(t, tc.expectEncoding, fetched.metadata.ContentEncoding)
+			assert.Equal(t, tc.expectTransferred, fetched.metadata.TransferredBytes)
+			assert.Equal(t, int64(len(page)), fetched.metadata.DecodedBytes)
+			assert.Equal(t, "test-server", fetched.metadata.Server)
+			assert.Equal(t, tc.expectRedirects, fetched.redirects)
+			assert.Equal(t, server.URL+tc.expectFinalPath, fetched.finalURL.String())
+			assert.GreaterOrEqual(t, fetched.metadata.Timing.TotalMs, fetched.metadata.Timing.TTFBMs)
+		})
+	}
+}
+
+func TestFetchPageRetries(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		count := requests[r.URL.Path]
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/unavailable-once":
+			if count == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "/unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("<html><body>ok</body></html>"))
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		name             string
+		path             string
+		maxAttempts      int
+		expectErrorCode  int
+		expectedRequests int
+	}{
+		{name: "Recovered after a retry", path: "/unavailable-once", maxAttempts: 3, expectedRequests: 2},
+		{name: "Attempts exhausted", path: "/unavailable", maxAttempts: 3, expectErrorCode: http.StatusServiceUnavailable, expectedRequests: 3},
+		{name: "Status not retryable", path: "/missing", maxAttempts: 3, expectErrorCode: http.StatusNotFound, expectedRequests: 1},
+		{name: "Retries not configured", path: "/unavailable-once", expectErrorCode: http.StatusServiceUnavailable, expectedRequests: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mu.Lock()
+			clear(requests)
+			mu.Unlock()
+
+			logger := log_utils.InitConsoleLogger()
+			config := &configurations.WebAnalyzerConfigurations{RetryMaxAttempts: tc.maxAttempts, RetryInitialBackoffMs: 1}
+			service := NewWebAnalyzerService(logger, nil, config, nil).(*webAnalyzerServiceImpl)
+
+			pageURL, _ := url.Parse(server.URL + tc.path)
+			fetched, err := service.fetchPage(context.Background(), pageURL)
+
+			mu.Lock()
+			assert.Equal(t, tc.expectedRequests, requests[tc.path])
+			mu.Unlock()
+
+			if tc.expectErrorCode != 0 {
+				assert.Nil(t, fetched)
+				ce, ok := err.(*custom_errors.CustomError)
+				if !ok {
+					t.Fatalf("error should be of type *CustomError, got %T: %v", err, err)
+				}
+				assert.Equal(t, tc.expectErrorCode, ce.Code)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, fetched.metadata.StatusCode)
+		})
+	}
+}
+
+func TestFetchPageTiming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow-redirect" {
+			time.Sleep(200 * time.Millisecond)
+			http.Redirect(w, r, "/page", http.StatusFound)
+			return
+		}
+		w.Write([]byte("<html><body>ok</body></html>"))
+	}))
+	defer server.Close()
+
+	logger := log_utils.InitConsoleLogger()
+	service := NewWebAnalyzerService(logger, nil, nil, nil).(*webAnalyzerServiceImpl)
+
+	pageURL, _ := url.Parse(server.URL + "/slow-redirect")
+	fetched, err := service.fetchPage(context.Background(), pageURL)
+
+	assert.Nil(t, err)
+	// the slow redirect hop counts towards the total time but not towards the time to first byte of the last request
+	assert.GreaterOrEqual(t, fetched.metadata.Timing.TotalMs, int64(200))
+	assert.Less(t, fetched.metadata.Timing.TTFBMs, int64(200))
+}
+
+func TestFetchPageRedirectLoop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusMovedPermanently)
+		case "/b":
+			http.Redirect(w, r, "/a", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	logger := log_utils.InitConsoleLogger()
+	service := NewWebAnalyzerService(logger, nil, nil, nil).(*webAnalyzerServiceImpl)
+	webAnalyzerUtils := web_analyzer_utils.NewWebAnalyzerUtils(logger, &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1})
+
+	pageURL, _ := url.Parse(server.URL + "/a")
+	fetched, err := service.fetchPage(context.Background(), pageURL)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusFound, fetched.metadata.StatusCode)
+	assert.Equal(t, []response_dtos.RedirectHop{
+		{Url: server.URL + "/a", StatusCode: http.StatusMovedPermanently, Location: "/b"},
+		{Url: server.URL + "/b", StatusCode: http.StatusFound, Location: "/a"},
+	}, fetched.redirects)
+	// the loop never reaches a page, so the redirect response is not treated as one
+	assert.True(t, fetched.redirectLoop)
+	assert.Nil(t, fetched.finalURL)
+	assert.Nil(t, fetched.body)
+
+	analysis := webAnalyzerUtils.DetectRedirectChainIssues(context.Background(), fetched.redirects, "")
+	var codes []string
+	for _, issue := range analysis.Issues {
+		codes = append(codes, issue.Code)
+	}
+	assert.Contains(t, codes, "redirect_loop")
+}
+
+func TestFetchPageUntrustedCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html><body>ok</body></html>"))
+	}))
+	defer server.Close()
+
+	logger := log_utils.InitConsoleLogger()
+	service := NewWebAnalyzerService(logger, nil, nil, nil).(*webAnalyzerServiceImpl)
+	webAnalyzerUtils := web_analyzer_utils.NewWebAnalyzerUtils(logger, &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1})
+
+	// the test server certificate is not issued by a trusted authority and is not valid for localhost
+	pageURL, _ := url.Parse(strings.Replace(server.URL, "127.0.0.1", "localhost", 1))
+	fetched, err := service.fetchPage(context.Background(), pageURL)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, fetched.response.TLS)
+
+	analysis := webAnalyzerUtils.DetectTLSDetails(context.Background(), fetched.response.TLS, fetched.finalURL.Hostname())
+	var codes []string
+	for _, issue := range analysis.Issues {
+		codes = append(codes, issue.Code)
+	}
+	assert.Equal(t, []string{"untrusted_certificate", "hostname_mismatch"}, codes)
+}
+
+func TestFetchPageRedirectHopCertificate(t *testing.T) {
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html><body>ok</body></html>"))
+	}))
+	defer page.Close()
+
+	// the redirecting server certificate is not issued by a trusted authority
+	redirector := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, page.URL+"/", http.StatusMovedPermanently)
+	}))
+	defer redirector.Close()
+
+	logger := log_utils.InitConsoleLogger()
+	service := NewWebAnalyzerService(logger, nil, nil, nil).(*webAnalyzerServiceImpl)
+
+	pageURL, _ := url.Parse(redirector.URL + "/")
+	fetched, err := service.fetchPage(context.Background(), pageURL)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fetched.redirects))
+	assert.NotEmpty(t, fetched.redirects[0].CertificateError)
+	assert.Nil(t, fetched.response.TLS)
+}
@@ -1,10 +1,13 @@
 package services
 
 import (
+	"bytes"
 	"context"
-	"fmt"
+	"crypto/tls"
+	"github.com/DaminduDilsara/web-analyzer/configurations"
 	"github.com/DaminduDilsara/web-analyzer/custom_errors"
 	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/request_dtos"
 	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
 	"github.com/DaminduDilsara/web-analyzer/internal/web_analyzer_utils"
 	"github.com/PuerkitoBio/goquery"
@@ -21,97 +24,242 @@ var typesOfHeadings = [...]string{"h1", "h2", "h3", "h4", "h5", "h6"}
 type webAnalyzerServiceImpl struct {
 	logger           log_utils.LoggerInterface
 	webAnalyzerUtils web_analyzer_utils.WebAnalyzerUtils
+	crawlConfig      *configurations.CrawlConfigurations
+	linkScope        string
 	httpClient       *http.Client
+	retryPolicy      *web_analyzer_utils.RetryPolicy
 }
 
 func NewWebAnalyzerService(
 	logger log_utils.LoggerInterface,
 	webAnalyzerUtils web_analyzer_utils.WebAnalyzerUtils,
+	webAnalyzerConfig *configurations.WebAnalyzerConfigurations,
+	crawlConfig *configurations.CrawlConfigurations,
 ) WebAnalyzerService {
 	return &webAnalyzerServiceImpl{
 		logger:           logger,
 		webAnalyzerUtils: webAnalyzerUtils,
+		crawlConfig:      crawlConfig,
+		linkScope:        linkScopeOf(webAnalyzerConfig),
 		httpClient: &http.Client{
-			Timeout: 6 * time.Second,
+			Timeout:   6 * time.Second,
+			Transport: newPageTransport(),
 		},
+		retryPolicy: web_analyzer_utils.NewRetryPolicy(webAnalyzerConfig),
 	}
 }
 
+// newPageTransport - creates the transport the pages are fetched with. the certificate of the page is not
+// verified by the transport, so pages with an expired, untrusted or mismatched certificate can still be
+// analyzed and the certificate problems are reported by DetectTLSDetails instead of failing the fetch.
+// the certificates of the redirect hops are verified by fetchPageAttempt and reported in the redirect analysis
+func newPageTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	return transport
+}
+
+// linkScopeOf - returns the link scope policy of webAnalyzerConfig, which is empty (exact_host) when it is nil
+func linkScopeOf(webAnalyzerConfig *configurations.WebAnalyzerConfigurations) string {
+	if webAnalyzerConfig == nil {
+		return ""
+	}
+	return webAnalyzerConfig.LinkScope
+}
+
 // NewWebAnalyzerServiceWithClient creates a new service with a custom HTTP client (for testing)
 func NewWebAnalyzerServiceWithClient(
 	logger log_utils.LoggerInterface,
 	webAnalyzerUtils web_analyzer_utils.WebAnalyzerUtils,
+	webAnalyzerConfig *configurations.WebAnalyzerConfigurations,
+	crawlConfig *configurations.CrawlConfigurations,
 	httpClient *http.Client,
 ) WebAnalyzerService {
 	return &webAnalyzerServiceImpl{
 		logger:           logger,
 		webAnalyzerUtils: webAnalyzerUtils,
+		crawlConfig:      crawlConfig,
+		linkScope:        linkScopeOf(webAnalyzerConfig),
 		httpClient:       httpClient,
+		retryPolicy:      web_analyzer_utils.NewRetryPolicy(webAnalyzerConfig),
 	}
 }
 
 // AnalyzeUrl - analyze the given url and return UrlAnalyzerResponse as response
 // - HTMLVersion - version of the web page
+// - Doctype - doctype of the web page with its identifiers and the quirks mode
+// - HTTPResponse - status, protocol, content type, encoding, size and timing of the http response
+// - Redirects - redirect chain which led to the final url and the problems found in it
+// - SecurityHeaders - verdict of each security header of the response and the overall grade
+// - TLS - tls version, cipher suite and certificate details of https pages
+// - Cookies - cookies set by the response and the problems found in their attributes
 // - Title - title of web page
 // - Headings - count of each heading type h1, h2, h3, h4, h5, h6
-// - InternalLinks - count of internal links
+// - HeadingOutline - heading tree of the web page and the problems found in its structure
+// - InternalLinks - count of internal links, resolved against the page url and compared by host
 // - ExternalLinks - count of external links
+// - NonHTTPLinks - count of mailto, tel, ftp, data and other non-http links by scheme
 // - InaccessibleLinks - count of inaccessible links
+// - MalformedLinks - count of links which cannot be resolved to a http url
+// - RestrictedLinks - count of links which exist but need authorization (401 and 403)
+// - RateLimitedLinks - count of links the server refused to check due to rate limiting (429)
+// - Links - accessibility report of each link
 // - LoginForm - if a login form present (true or false)
-func (w *webAnalyzerServiceImpl) AnalyzeUrl(ctx context.Context, parsedURL *url.URL) (*response_dtos.UrlAnalyzerResponse, error) {
+// - Forms - method, action, fields and classification of each form and the problems found in them
+// - Authentication - password and identifier-first forms, sso buttons, OAuth and SAML endpoints, captchas and one-time-code inputs
+// - SEO - seo metadata of the web page and the problems found in it
+// - StructuredData - open graph, twitter card and JSON-LD data of the web page
+// - Accessibility - accessibility problems found in the web page
+// - MixedContent - subresources of https pages loaded over http
+// - Resources - scripts, stylesheets, images, media, font preloads and iframes of the web page
+// - ThirdParties - registrable domains referenced by the links and resources of the web page and the known trackers among them
+// - Technologies - cms, frameworks, libraries, analytics and servers the web page is built with
+// the link accessibility check is skipped when options.SkipLinkCheck is set and the resources
+// are checked by the link checker when options.CheckResources is set
+func (w *webAnalyzerServiceImpl) AnalyzeUrl(ctx context.Context, parsedURL *url.URL, options request_dtos.AnalyzeOptions) (*response_dtos.UrlAnalyzerResponse, error) {
+	result, _, _, err := w.analyzePage(ctx, parsedURL, options)
+	return result, err
+}
+
+// analyzePage - fetches the page and runs it through the detectors.
+// returns the analysis result along with the absolute urls of the links found in the page and the final url of the page.
+// a url which redirects in a loop never reaches a page, so only its response and redirect chain are analyzed
+// and no links or final url are returned
+func (w *webAnalyzerServiceImpl) analyzePage(ctx context.Context, parsedURL *url.URL, options request_dtos.AnalyzeOptions) (*response_dtos.UrlAnalyzerResponse, []string, *url.URL, error) {
 
-	resp, err := w.httpClient.Get(parsedURL.String())
+	page, err := w.fetchPage(ctx, parsedURL)
 	if err != nil {
-		w.logger.ErrorWithContext(ctx, "Unable to fetch data from the url", err, log_utils.SetLogFile(webAnalyzerServiceLogPrefix))
-		if _, ok := err.(*url.Error); ok {
-			if strings.Contains(err.Error(), "no such host") {
-				return nil, custom_errors.NewCustomError(http.StatusNotFound, "server not found for the given url or domain does not exist", err)
-			}
-			return nil, custom_errors.NewCustomError(http.StatusBadGateway, "failed to connect to the given server", err)
-		}
-		return nil, custom_errors.NewCustomError(http.StatusInternalServerError, "unable to fetch data from the given url", err)
+		return nil, nil, nil, err
 	}
-	defer resp.Body.Close()
 
-	if (resp.StatusCode < http.StatusOK) || (resp.StatusCode >= http.StatusMultipleChoices) {
-		err = fmt.Errorf("unexpected HTTP status code: %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
-		w.logger.ErrorWithContext(ctx, "unexpected HTTP status code", err, log_utils.SetLogFile(webAnalyzerServiceLogPrefix))
-		return nil, custom_errors.NewCustomError(resp.StatusCode, "unexpected HTTP status code", err)
+	if page.redirectLoop {
+		return &response_dtos.UrlAnalyzerResponse{
+			HTTPResponse: page.metadata,
+			Redirects:    w.webAnalyzerUtils.DetectRedirectChainIssues(ctx, page.redirects, ""),
+		}, nil, nil, nil
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page.body))
 	if err != nil {
 		w.logger.ErrorWithContext(ctx, "response cannot parse to html", err, log_utils.SetLogFile(webAnalyzerServiceLogPrefix))
-		return nil, custom_errors.NewCustomError(http.StatusInternalServerError, "response cannot parse to html", err)
+		return nil, nil, nil, custom_errors.NewCustomError(http.StatusInternalServerError, "response cannot parse to html", err)
 	}
 
-	htmlText, err := doc.Html()
-	if err != nil {
-		w.logger.ErrorWithContext(ctx, "cannot extract html text from document", err, log_utils.SetLogFile(webAnalyzerServiceLogPrefix))
-		return nil, custom_errors.NewCustomError(http.StatusInternalServerError, "cannot extract html text from document", err)
-	}
+	redirectAnalysis := w.webAnalyzerUtils.DetectRedirectChainIssues(ctx, page.redirects, page.finalURL.String())
 
-	htmlVersion := w.webAnalyzerUtils.DetectHTMLVersion(ctx, htmlText)
+	securityHeaders := w.webAnalyzerUtils.DetectSecurityHeaders(ctx, page.response.Header, page.finalURL)
+
+	tlsAnalysis := w.webAnalyzerUtils.DetectTLSDetails(ctx, page.response.TLS, page.finalURL.Hostname())
+
+	cookieAnalysis := w.webAnalyzerUtils.DetectCookies(ctx, page.response.Header)
+
+	doctype := w.webAnalyzerUtils.DetectHTMLVersion(ctx, page.body) // the doctype is parsed from the raw bytes since goquery may rewrite it
 
 	pageTitle := w.webAnalyzerUtils.DetectPageTitle(ctx, doc)
 
 	isLoginFormExist := w.webAnalyzerUtils.DetectLoginForm(ctx, doc)
 
+	forms := w.webAnalyzerUtils.DetectForms(ctx, doc, page.finalURL)
+
+	authentication := w.webAnalyzerUtils.DetectAuthentication(ctx, doc, page.finalURL)
+
 	headingData := w.webAnalyzerUtils.DetectHeaders(ctx, doc, typesOfHeadings)
 
-	internalLinks, externalLinks, allLinks := w.webAnalyzerUtils.DetectLinks(ctx, doc, parsedURL.Host)
+	headingOutline := w.webAnalyzerUtils.DetectHeadingOutline(ctx, doc)
+
+	seoAnalysis := w.webAnalyzerUtils.DetectSEOMetadata(ctx, doc, page.finalURL)
+
+	structuredData := w.webAnalyzerUtils.DetectStructuredData(ctx, doc)
+
+	accessibility := w.webAnalyzerUtils.DetectAccessibilityIssues(ctx, doc)
+
+	mixedContent := w.webAnalyzerUtils.DetectMixedContent(ctx, doc, page.finalURL)
 
-	inaccessibleLinks := w.webAnalyzerUtils.IsLinksAccessible(ctx, allLinks, parsedURL)
+	resources := w.webAnalyzerUtils.DetectResources(ctx, doc, page.finalURL)
+	if options.CheckResources {
+		w.checkResources(ctx, &resources, page.finalURL)
+	}
+
+	baseURL := web_analyzer_utils.DocumentBaseURL(doc, page.finalURL)
+
+	internalLinks, externalLinks, nonHTTPLinks, allLinks := w.webAnalyzerUtils.DetectLinks(ctx, doc, page.finalURL)
+	linkURLs := resolveLinks(baseURL, allLinks)
+
+	thirdParties := w.webAnalyzerUtils.DetectThirdPartyDomains(ctx, page.finalURL, linkURLs, resources)
+
+	technologies := w.webAnalyzerUtils.DetectTechnologies(ctx, doc, page.response.Header)
+
+	var linkReport web_analyzer_utils.LinkCheckReport
+	if !options.SkipLinkCheck {
+		linkReport = w.webAnalyzerUtils.IsLinksAccessible(ctx, allLinks, baseURL)
+	}
 
 	result := response_dtos.UrlAnalyzerResponse{
-		HTMLVersion:       htmlVersion,
+		HTMLVersion:       doctype.Version,
+		Doctype:           doctype,
+		HTTPResponse:      page.metadata,
+		Redirects:         redirectAnalysis,
+		SecurityHeaders:   securityHeaders,
+		TLS:               tlsAnalysis,
+		Cookies:           cookieAnalysis,
 		Title:             pageTitle,
 		Headings:          headingData,
+		HeadingOutline:    headingOutline,
 		InternalLinks:     internalLinks,
 		ExternalLinks:     externalLinks,
-		InaccessibleLinks: inaccessibleLinks,
+		NonHTTPLinks:      nonHTTPLinks,
+		InaccessibleLinks: linkReport.InaccessibleLinks,
+		MalformedLinks:    linkReport.MalformedLinks,
+		RestrictedLinks:   linkReport.RestrictedLinks,
+		RateLimitedLinks:  linkReport.RateLimitedLinks,
+		Links:             linkReport.Links,
 		LoginForm:         isLoginFormExist,
+		Forms:             forms,
+		Authentication:    authentication,
+		SEO:               seoAnalysis,
+		StructuredData:    structuredData,
+		Accessibility:     accessibility,
+		MixedContent:      mixedContent,
+		Resources:         resources,
+		ThirdParties:      thirdParties,
+		Technologies:      technologies,
 	}
 
-	return &result, nil
+	return &result, linkURLs, page.finalURL, nil
+}
+
+// resolveLinks - resolves the links against the base url of the page. links which cannot be parsed are dropped
+func resolveLinks(baseURL *url.URL, links []string) []string {
+	linkURLs := make([]string, 0, len(links))
+	for _, link := range links {
+		ref, err := url.Parse(strings.TrimSpace(link))
+		if err != nil {
+			continue
+		}
+		linkURLs = append(linkURLs, baseURL.ResolveReference(ref).String())
+	}
+	return linkURLs
+}
+
+// checkResources - checks the accessibility of the external resources in the inventory with the link checker
+// and sets the status of each resource. the resource checks are not reported as link progress
+func (w *webAnalyzerServiceImpl) checkResources(ctx context.Context, inventory *response_dtos.ResourceInventory, pageURL *url.URL) {
+	resourceURLs := web_analyzer_utils.ResourceURLs(*inventory)
+	if len(resourceURLs) == 0 {
+		return
+	}
+
+	report := w.webAnalyzerUtils.IsLinksAccessible(web_analyzer_utils.WithLinkProgress(ctx, nil), resourceURLs, pageURL)
+
+	statuses := make(map[string]response_dtos.LinkStatus)
+	for _, link := range report.Links {
+		statuses[link.Href] = link
+	}
+	for i, resource := range inventory.Resources {
+		if status, ok := statuses[resource.Url]; ok {
+			inventory.Resources[i].Status = status.Status
+			inventory.Resources[i].StatusCode = status.StatusCode
+		}
+	}
+	inventory.InaccessibleResources = report.InaccessibleLinks
 }
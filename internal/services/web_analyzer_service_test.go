@@ -12,7 +12,9 @@ import (
 
 	"github.com/DaminduDilsara/web-analyzer/custom_errors"
 	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/request_dtos"
 	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/DaminduDilsara/web-analyzer/internal/web_analyzer_utils"
 	"github.com/DaminduDilsara/web-analyzer/mocks"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -48,16 +50,46 @@ func TestAnalyzeUrl(t *testing.T) {
 		name              string
 		mockResp          *http.Response
 		mockErr           error
+		options           request_dtos.AnalyzeOptions
 		mockUtilsFn       func(*mocks.MockWebAnalyzerUtils)
 		expectError       bool
 		expectResult      *response_dtos.UrlAnalyzerResponse
 		expectCustomError *custom_errors.CustomError
 	}
 
-	html := ``
+	html := `<!DOCTYPE html><html><head><title>Test Page</title></head><body></body></html>`
 	parsedURL, _ := url.Parse("http://test.test")
 	ctx := context.Background()
 
+	expectedDoctype := response_dtos.Doctype{
+		Present: true,
+		Raw:     "<!DOCTYPE html>",
+		Name:    "html",
+		Version: "HTML 5",
+		Mode:    "no-quirks",
+	}
+
+	expectedRedirects := response_dtos.RedirectAnalysis{
+		Hops:     []response_dtos.RedirectHop{},
+		FinalUrl: "http://test.test",
+		Issues:   []response_dtos.AnalysisIssue{},
+	}
+
+	expectedSecurityHeaders := response_dtos.SecurityHeadersAnalysis{
+		Grade:   "F",
+		Headers: []response_dtos.SecurityHeaderVerdict{{Header: "Strict-Transport-Security", Verdict: "missing", Message: "the header is not set"}},
+		Issues:  []response_dtos.AnalysisIssue{},
+	}
+
+	expectedTLS := response_dtos.TLSAnalysis{
+		Issues: []response_dtos.AnalysisIssue{},
+	}
+
+	expectedCookies := response_dtos.CookieAnalysis{
+		Cookies: []response_dtos.CookieDetails{{Name: "session_id", Path: "/", SessionCookie: true, Secure: true, HttpOnly: true, SameSite: "Lax", Size: 13, LooksLikeAuth: true}},
+		Issues:  []response_dtos.AnalysisIssue{},
+	}
+
 	expectedHeadings := map[string]int{
 		"h1": 1,
 		"h2": 0,
@@ -67,15 +99,148 @@ func TestAnalyzeUrl(t *testing.T) {
 		"h6": 0,
 	}
 
+	expectedLinkReport := web_analyzer_utils.LinkCheckReport{
+		InaccessibleLinks: 0,
+		Links: []response_dtos.LinkStatus{
+			{Href: "/internal", Url: "http://test.test/internal", FinalUrl: "http://test.test/internal", Status: "accessible", StatusCode: 200},
+			{Href: "http://external.test", Url: "http://external.test", FinalUrl: "http://external.test", Status: "accessible", StatusCode: 200},
+		},
+	}
+
+	expectedHeadingOutline := response_dtos.HeadingOutline{
+		Outline: []*response_dtos.HeadingNode{
+			{Level: 1, Text: "Test Page", Children: []*response_dtos.HeadingNode{{Level: 2, Text: "Section"}}},
+		},
+		Issues: []response_dtos.AnalysisIssue{},
+	}
+
+	expectedSEO := response_dtos.SEOAnalysis{
+		Title:       "Test Page",
+		TitleLength: 9,
+		Issues:      []response_dtos.AnalysisIssue{{Code: "missing_description", Severity: "warning", Message: "the page has no meta description"}},
+	}
+
+	expectedStructuredData := response_dtos.StructuredData{
+		OpenGraph: map[string][]string{"og:title": {"Test Page"}},
+		JSONLD:    map[string][]map[string]interface{}{"WebPage": {{"@type": "WebPage", "name": "Test Page"}}},
+	}
+
+	expectedAccessibility := response_dtos.AccessibilityAnalysis{
+		Lang:    "en",
+		Summary: map[string]int{"missing_alt": 1},
+		Issues:  []response_dtos.AnalysisIssue{{Code: "missing_alt", Severity: "error", Message: "the image \"logo.png\" has no alt attribute", Path: "html > body > img"}},
+	}
+
+	expectedMixedContent := response_dtos.MixedContentAnalysis{
+		Findings: []response_dtos.MixedContentFinding{},
+	}
+
+	expectedResources := response_dtos.ResourceInventory{
+		Resources: []response_dtos.Resource{
+			{Type: "script", Url: "http://test.test/app.js", Defer: true, Path: "html > head > script"},
+			{Type: "image", Url: "http://cdn.test/logo.png", ThirdParty: true, Path: "html > body > img"},
+		},
+		Counts:     map[string]int{"script": 1, "image": 1},
+		FirstParty: 1,
+		ThirdParty: 1,
+	}
+
+	expectedThirdParties := response_dtos.ThirdPartyAnalysis{
+		FirstPartyDomain: "test.test",
+		Domains: []response_dtos.ThirdPartyDomain{
+			{Domain: "cdn.test", Hosts: []string{"cdn.test"}, ResourceReferences: 1, TotalReferences: 1},
+			{Domain: "external.test", Hosts: []string{"external.test"}, LinkReferences: 1, TotalReferences: 1},
+		},
+		Categories: map[string]int{},
+	}
+
+	expectedTechnologies := []response_dtos.Technology{
+		{Name: "jQuery", Category: "javascript_library", Version: "3.7.1", Confidence: 100, Evidence: []string{"script /jquery-3.7.1.min.js"}},
+	}
+
+	expectedForms := response_dtos.FormAnalysis{
+		Forms: []response_dtos.Form{
+			{
+				Classification: "login",
+				Method:         "POST",
+				Action:         "http://test.test/login",
+				Fields: []response_dtos.FormField{
+					{Name: "user", Type: "text"},
+					{Name: "pass", Type: "password"},
+				},
+				Path: "html > body > form",
+			},
+		},
+		Counts: map[string]int{"login": 1},
+		Issues: []response_dtos.AnalysisIssue{},
+	}
+
+	expectedAuthentication := response_dtos.AuthenticationAnalysis{
+		LoginDetected:     true,
+		PasswordForm:      true,
+		SSOButtons:        []response_dtos.SSOButton{},
+		OAuthEndpoints:    []response_dtos.OAuthEndpoint{},
+		SAMLEndpoints:     []response_dtos.AuthEndpoint{},
+		Captchas:          []response_dtos.Captcha{},
+		OneTimeCodeFields: []string{},
+	}
+
+	// expectPageDetectors - sets the expectations of the detectors which run for every analyzed page
+	expectPageDetectors := func(m *mocks.MockWebAnalyzerUtils) {
+		m.EXPECT().DetectRedirectChainIssues(ctx, []response_dtos.RedirectHop(nil), "http://test.test").Return(expectedRedirects)
+		m.EXPECT().DetectSecurityHeaders(ctx, gomock.Any(), parsedURL).Return(expectedSecurityHeaders)
+		m.EXPECT().DetectTLSDetails(ctx, nil, "test.test").Return(expectedTLS)
+		m.EXPECT().DetectCookies(ctx, gomock.Any()).Return(expectedCookies)
+		m.EXPECT().DetectHTMLVersion(ctx, []byte(html)).Return(expectedDoctype)
+		m.EXPECT().DetectPageTitle(ctx, gomock.Any()).Return("Test Page")
+		m.EXPECT().DetectLoginForm(ctx, gomock.Any()).Return(true)
+		m.EXPECT().DetectForms(ctx, gomock.Any(), parsedURL).Return(expectedForms)
+		m.EXPECT().DetectAuthentication(ctx, gomock.Any(), parsedURL).Return(expectedAuthentication)
+		m.EXPECT().DetectHeaders(ctx, gomock.Any(), typesOfHeadings).Return(expectedHeadings)
+		m.EXPECT().DetectHeadingOutline(ctx, gomock.Any()).Return(expectedHeadingOutline)
+		m.EXPECT().DetectSEOMetadata(ctx, gomock.Any(), parsedURL).Return(expectedSEO)
+		m.EXPECT().DetectStructuredData(ctx, gomock.Any()).Return(expectedStructuredData)
+		m.EXPECT().DetectAccessibilityIssues(ctx, gomock.Any()).Return(expectedAccessibility)
+		m.EXPECT().DetectMixedContent(ctx, gomock.Any(), parsedURL).Return(expectedMixedContent)
+		m.EXPECT().DetectResources(ctx, gomock.Any(), parsedURL).Return(expectedResources)
+		m.EXPECT().DetectLinks(ctx, gomock.Any(), parsedURL).Return(1, 1, map[string]int{"mailto": 1}, []string{"/internal", "http://external.test"})
+		m.EXPECT().DetectThirdPartyDomains(ctx, parsedURL, []string{"http://test.test/internal", "http://external.test"}, gomock.Any()).Return(expectedThirdParties)
+		m.EXPECT().DetectTechnologies(ctx, gomock.Any(), gomock.Any()).Return(expectedTechnologies)
+	}
+
 	expectedResponse := &response_dtos.UrlAnalyzerResponse{
 		HTMLVersion:       "HTML 5",
+		Doctype:           expectedDoctype,
+		Redirects:         expectedRedirects,
+		SecurityHeaders:   expectedSecurityHeaders,
+		TLS:               expectedTLS,
+		Cookies:           expectedCookies,
 		Title:             "Test Page",
 		Headings:          expectedHeadings,
+		HeadingOutline:    expectedHeadingOutline,
 		InternalLinks:     1,
 		ExternalLinks:     1,
+		NonHTTPLinks:      map[string]int{"mailto": 1},
 		InaccessibleLinks: 0,
+		Links:             expectedLinkReport.Links,
 		LoginForm:         true,
+		Forms:             expectedForms,
+		Authentication:    expectedAuthentication,
+		SEO:               expectedSEO,
+		StructuredData:    expectedStructuredData,
+		Accessibility:     expectedAccessibility,
+		MixedContent:      expectedMixedContent,
+		Resources:         expectedResources,
+		ThirdParties:      expectedThirdParties,
+		Technologies:      expectedTechnologies,
+	}
+
+	checkedResources := expectedResources
+	checkedResources.Resources = []response_dtos.Resource{
+		{Type: "script", Url: "http://test.test/app.js", Defer: true, Path: "html > head > script", Status: "accessible", StatusCode: 200},
+		{Type: "image", Url: "http://cdn.test/logo.png", ThirdParty: true, Path: "html > body > img", Status: "inaccessible", StatusCode: 404},
 	}
+	checkedResources.InaccessibleResources = 1
 
 	cases := []testCase{
 		{
@@ -85,17 +250,77 @@ func TestAnalyzeUrl(t *testing.T) {
 				Body:       ioutil.NopCloser(bytes.NewBufferString(html)),
 			},
 			mockUtilsFn: func(m *mocks.MockWebAnalyzerUtils) {
-				m.EXPECT().DetectHTMLVersion(ctx, gomock.Any()).Return("HTML 5")
-				m.EXPECT().DetectPageTitle(ctx, gomock.Any()).Return("Test Page")
-				m.EXPECT().DetectLoginForm(ctx, gomock.Any()).Return(true)
-				m.EXPECT().DetectHeaders(ctx, gomock.Any(), typesOfHeadings).Return(expectedHeadings)
-				m.EXPECT().DetectLinks(ctx, gomock.Any(), "test.test").Return(1, 1, []string{"/internal", "http://external.test"})
-				m.EXPECT().IsLinksAccessible(ctx, []string{"/internal", "http://external.test"}, parsedURL).Return(0)
+				expectPageDetectors(m)
+				m.EXPECT().IsLinksAccessible(ctx, []string{"/internal", "http://external.test"}, parsedURL).Return(expectedLinkReport)
 			},
 			expectResult:      expectedResponse,
 			expectError:       false,
 			expectCustomError: nil,
 		},
+		{
+			name: "Skip link check",
+			mockResp: &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(html)),
+			},
+			options: request_dtos.AnalyzeOptions{SkipLinkCheck: true},
+			mockUtilsFn: func(m *mocks.MockWebAnalyzerUtils) {
+				expectPageDetectors(m)
+			},
+			expectResult: &response_dtos.UrlAnalyzerResponse{
+				HTMLVersion:       "HTML 5",
+				Doctype:           expectedDoctype,
+				Redirects:         expectedRedirects,
+				SecurityHeaders:   expectedSecurityHeaders,
+				TLS:               expectedTLS,
+				Cookies:           expectedCookies,
+				Title:             "Test Page",
+				Headings:          expectedHeadings,
+				HeadingOutline:    expectedHeadingOutline,
+				InternalLinks:     1,
+				ExternalLinks:     1,
+				NonHTTPLinks:      map[string]int{"mailto": 1},
+				InaccessibleLinks: 0,
+				LoginForm:         true,
+				Forms:             expectedForms,
+				Authentication:    expectedAuthentication,
+				SEO:               expectedSEO,
+				StructuredData:    expectedStructuredData,
+				Accessibility:     expectedAccessibility,
+				MixedContent:      expectedMixedContent,
+				Resources:         expectedResources,
+				ThirdParties:      expectedThirdParties,
+				Technologies:      expectedTechnologies,
+			},
+			expectError:       false,
+			expectCustomError: nil,
+		},
+		{
+			name: "Check resources",
+			mockResp: &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(html)),
+			},
+			options: request_dtos.AnalyzeOptions{SkipLinkCheck: true, CheckResources: true},
+			mockUtilsFn: func(m *mocks.MockWebAnalyzerUtils) {
+				expectPageDetectors(m)
+				m.EXPECT().IsLinksAccessible(gomock.Any(), []string{"http://test.test/app.js", "http://cdn.test/logo.png"}, parsedURL).Return(web_analyzer_utils.LinkCheckReport{
+					InaccessibleLinks: 1,
+					Links: []response_dtos.LinkStatus{
+						{Href: "http://test.test/app.js", Url: "http://test.test/app.js", Status: "accessible", StatusCode: 200},
+						{Href: "http://cdn.test/logo.png", Url: "http://cdn.test/logo.png", Status: "inaccessible", StatusCode: 404, ErrorClass: "4xx"},
+					},
+				})
+			},
+			expectResult: func() *response_dtos.UrlAnalyzerResponse {
+				result := *expectedResponse
+				result.Links = nil
+				result.Resources = checkedResources
+				return &result
+			}(),
+			expectError:       false,
+			expectCustomError: nil,
+		},
 		{
 			name:              "HTTP error (no such host)",
 			mockErr:           &url.Error{Op: "Get", URL: "http://test.test", Err: fmt.Errorf("no such host")},
@@ -153,8 +378,8 @@ func TestAnalyzeUrl(t *testing.T) {
 
 			mockClient := mockHTTPClient(tc.mockResp, tc.mockErr)
 
-			service := NewWebAnalyzerServiceWithClient(logger, mockUtils, mockClient)
-			result, customErr := service.AnalyzeUrl(ctx, parsedURL)
+			service := NewWebAnalyzerServiceWithClient(logger, mockUtils, nil, nil, mockClient)
+			result, customErr := service.AnalyzeUrl(ctx, parsedURL, tc.options)
 
 			if tc.expectError {
 				assert.Nil(t, result)
@@ -171,12 +396,29 @@ func TestAnalyzeUrl(t *testing.T) {
 				assert.Nil(t, customErr)
 				assert.NotNil(t, result)
 				assert.Equal(t, tc.expectResult.HTMLVersion, result.HTMLVersion)
+				assert.Equal(t, tc.expectResult.Doctype, result.Doctype)
+				assert.Equal(t, tc.expectResult.Redirects, result.Redirects)
+				assert.Equal(t, tc.expectResult.SecurityHeaders, result.SecurityHeaders)
+				assert.Equal(t, tc.expectResult.TLS, result.TLS)
+				assert.Equal(t, tc.expectResult.Cookies, result.Cookies)
 				assert.Equal(t, tc.expectResult.Title, result.Title)
 				assert.Equal(t, tc.expectResult.Headings, result.Headings)
+				assert.Equal(t, tc.expectResult.HeadingOutline, result.HeadingOutline)
 				assert.Equal(t, tc.expectResult.InternalLinks, result.InternalLinks)
 				assert.Equal(t, tc.expectResult.ExternalLinks, result.ExternalLinks)
+				assert.Equal(t, tc.expectResult.NonHTTPLinks, result.NonHTTPLinks)
 				assert.Equal(t, tc.expectResult.InaccessibleLinks, result.InaccessibleLinks)
+				assert.Equal(t, tc.expectResult.Links, result.Links)
 				assert.Equal(t, tc.expectResult.LoginForm, result.LoginForm)
+				assert.Equal(t, tc.expectResult.Forms, result.Forms)
+				assert.Equal(t, tc.expectResult.Authentication, result.Authentication)
+				assert.Equal(t, tc.expectResult.SEO, result.SEO)
+				assert.Equal(t, tc.expectResult.StructuredData, result.StructuredData)
+				assert.Equal(t, tc.expectResult.Accessibility, result.Accessibility)
+				assert.Equal(t, tc.expectResult.MixedContent, result.MixedContent)
+				assert.Equal(t, tc.expectResult.Resources, result.Resources)
+				assert.Equal(t, tc.expectResult.ThirdParties, result.ThirdParties)
+				assert.Equal(t, tc.expectResult.Technologies, result.Technologies)
 			}
 		})
 	}
@@ -32,6 +32,10 @@ func (e *Engine) GetEngine() *gin.Engine {
 	v1Group := engine.Group("/api/v1")
 	{
 		v1Group.POST("analyze", e.controller.AnalyzeController)
+		v1Group.POST("jobs", e.controller.SubmitJobController)
+		v1Group.GET("jobs/:id", e.controller.GetJobController)
+		v1Group.POST("batch", e.controller.BatchAnalyzeController)
+		v1Group.POST("crawl", e.controller.CrawlController)
 	}
 
 	return engine
@@ -0,0 +1,201 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"strings"
+)
+
+// input types which are not labeled by the user or get their name from the value attribute
+var unlabeledInputTypes = map[string]bool{
+	"hidden": true,
+	"submit": true,
+	"reset":  true,
+	"button": true,
+	"image":  true,
+}
+
+// DetectAccessibilityIssues - audits the web page for the common accessibility problems
+// - images without an alt attribute
+// - form controls without an associated label, aria-label or aria-labelledby
+// - links and buttons without an accessible name
+// - missing lang attribute in the html element
+// - duplicate id attributes
+// every issue carries a css selector like path of the element it was found in
+func (w *webAnalyzerUtilsImpl) DetectAccessibilityIssues(ctx context.Context, doc *goquery.Document) response_dtos.AccessibilityAnalysis {
+	analysis := response_dtos.AccessibilityAnalysis{
+		Summary: make(map[string]int),
+		Issues:  []response_dtos.AnalysisIssue{},
+	}
+
+	addIssue := func(issue response_dtos.AnalysisIssue) {
+		analysis.Issues = append(analysis.Issues, issue)
+		analysis.Summary[issue.Code]++
+	}
+
+	analysis.Lang = strings.TrimSpace(doc.Find("html").First().AttrOr("lang", ""))
+	if analysis.Lang == "" {
+		addIssue(newElementIssue("missing_lang", IssueSeverityError, "the html element has no lang attribute", "html"))
+	}
+
+	doc.Find("img").Each(func(i int, s *goquery.Selection) {
+		if _, exists := s.Attr("alt"); !exists {
+			addIssue(newElementIssue("missing_alt", IssueSeverityError, fmt.Sprintf("the image %q has no alt attribute", s.AttrOr("src", "")), elementPath(s)))
+		}
+	})
+
+	doc.Find("input, select, textarea").Each(func(i int, s *goquery.Selection) {
+		if goquery.NodeName(s) == "input" && unlabeledInputTypes[strings.ToLower(s.AttrOr("type", ""))] {
+			return
+		}
+		if !isLabeled(doc, s) {
+			addIssue(newElementIssue("unlabeled_control", IssueSeverityError, fmt.Sprintf("the %v control %q has no label", goquery.NodeName(s), s.AttrOr("name", "")), elementPath(s)))
+		}
+	})
+
+	doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
+		if accessibleName(doc, s) == "" {
+			addIssue(newElementIssue("empty_link", IssueSeverityError, fmt.Sprintf("the link to %q has no accessible name", s.AttrOr("href", "")), elementPath(s)))
+		}
+	})
+
+	doc.Find(`button, input[type="button"]`).Each(func(i int, s *goquery.Selection) {
+		if accessibleName(doc, s) == "" {
+			addIssue(newElementIssue("empty_button", IssueSeverityError, "the button has no accessible name", elementPath(s)))
+		}
+	})
+
+	seenIds := make(map[string]bool)
+	doc.Find("[id]").Each(func(i int, s *goquery.Selection) {
+		id := s.AttrOr("id", "")
+		if seenIds[id] {
+			addIssue(newElementIssue("duplicate_id", IssueSeverityWarning, fmt.Sprintf("the id %q is used by more than one element", id), elementPath(s)))
+		}
+		seenIds[id] = true
+	})
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified %v accessibility issues", len(analysis.Issues)), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+
+	return analysis
+}
+
+// isLabeled - checks if the form control is named by aria-label, aria-labelledby, title,
+// a label pointing to its id or a label wrapping it
+func isLabeled(doc *goquery.Document, s *goquery.Selection) bool {
+	if ariaName(doc, s) != "" || strings.TrimSpace(s.AttrOr("title", "")) != "" {
+		return true
+	}
+
+	if id := s.AttrOr("id", ""); id != "" {
+		hasLabel := doc.Find("label[for]").FilterFunction(func(i int, label *goquery.Selection) bool {
+			return label.AttrOr("for", "") == id
+		}).Length() > 0
+		if hasLabel {
+			return true
+		}
+	}
+
+	return s.Closest("label").Length() > 0
+}
+
+// accessibleName - returns the name announced by screen readers for a link or a button, which is the
+// aria-label, the text referred by aria-labelledby, the text content together with the alt text of
+// the images inside, the value of an input button or the title
+func accessibleName(doc *goquery.Document, s *goquery.Selection) string {
+	if name := ariaName(doc, s); name != "" {
+		return name
+	}
+
+	if goquery.NodeName(s) == "input" {
+		if value := strings.TrimSpace(s.AttrOr("value", "")); value != "" {
+			return value
+		}
+	} else {
+		name := strings.TrimSpace(s.Text())
+		s.Find("img[alt]").Each(func(i int, img *goquery.Selection) {
+			name += strings.TrimSpace(img.AttrOr("alt", ""))
+		})
+		if name != "" {
+			return name
+		}
+	}
+
+	return strings.TrimSpace(s.AttrOr("title", ""))
+}
+
+// ariaName - returns the aria-label of the element or the text of the elements referred by aria-labelledby
+func ariaName(doc *goquery.Document, s *goquery.Selection) string {
+	if label := strings.TrimSpace(s.AttrOr("aria-label", "")); label != "" {
+		return label
+	}
+
+	var texts []string
+	for _, id := range strings.Fields(s.AttrOr("aria-labelledby", "")) {
+		doc.Find("[id]").EachWithBreak(func(i int, labelledBy *goquery.Selection) bool {
+			if labelledBy.AttrOr("id", "") != id {
+				return true
+			}
+			if text := strings.TrimSpace(labelledBy.Text()); text != "" {
+				texts = append(texts, text)
+			}
+			return false
+		})
+	}
+	return strings.Join(texts, " ")
+}
+
+// elementPath - builds a css selector like path of the element from the html element,
+// e.g. html > body > div#main > ul > li:nth-of-type(2) > a
+func elementPath(s *goquery.Selection) string {
+	var parts []string
+
+	for node := s.Get(0); node != nil && node.Type == html.ElementNode; node = node.Parent {
+		part := node.Data
+		if id := attrValue(node, "id"); id != "" {
+			part += "#" + id
+		} else if position, total := nthOfType(node); total > 1 {
+			part += fmt.Sprintf(":nth-of-type(%v)", position)
+		}
+		parts = append([]string{part}, parts...)
+	}
+
+	return strings.Join(parts, " > ")
+}
+
+// nthOfType - returns the position of the node among its siblings with the same tag and the count of those siblings
+func nthOfType(node *html.Node) (int, int) {
+	if node.Parent == nil {
+		return 1, 1
+	}
+
+	position, total := 0, 0
+	for sibling := node.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+		if sibling.Type != html.ElementNode || sibling.Data != node.Data {
+			continue
+		}
+		total++
+		if sibling == node {
+			position = total
+		}
+	}
+	return position, total
+}
+
+func attrValue(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func newElementIssue(code string, severity string, message string, path string) response_dtos.AnalysisIssue {
+	issue := newIssue(code, severity, message)
+	issue.Path = path
+	return issue
+}
@@ -0,0 +1,96 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestDetectAccessibilityIssues(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+	config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1}
+	utils := NewWebAnalyzerUtils(logger, config)
+
+	tests := []struct {
+		name          string
+		html          string
+		expectedLang  string
+		expectedPaths map[string][]string
+	}{
+		{
+			name: "Accessible Page",
+			html: `<html lang="en"><body>
+				<img src="logo.png" alt="Logo">
+				<img src="spacer.png" alt="">
+				<label for="email">Email</label><input id="email" type="email">
+				<label>Password <input type="password"></label>
+				<input type="text" aria-label="Search">
+				<span id="country-label">Country</span><select aria-labelledby="country-label"></select>
+				<input type="hidden" name="token">
+				<input type="submit">
+				<a href="/home">Home</a>
+				<a href="/profile"><img src="avatar.png" alt="Profile"></a>
+				<button aria-label="Close"></button>
+				<input type="button" value="Cancel">
+				</body></html>`,
+			expectedLang:  "en",
+			expectedPaths: map[string][]string{},
+		},
+		{
+			name: "Inaccessible Page",
+			html: `<html><body>
+				<div id="main">
+					<img src="a.png">
+					<ul><li><a href="/a">A</a></li><li><a href="/b"></a></li></ul>
+					<textarea></textarea>
+					<button><i class="icon"></i></button>
+				</div>
+				<div id="main"><input type="text" title=""></div>
+				<input type="button">
+				</body></html>`,
+			expectedLang: "",
+			expectedPaths: map[string][]string{
+				"missing_lang":      {"html"},
+				"missing_alt":       {"html > body > div#main > img"},
+				"unlabeled_control": {"html > body > div#main > textarea", "html > body > div#main > input"},
+				"empty_link":        {"html > body > div#main > ul > li:nth-of-type(2) > a"},
+				"empty_button":      {"html > body > div#main > button", "html > body > input"},
+				"duplicate_id":      {"html > body > div#main"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatalf("Failed to parse HTML: %v", err)
+			}
+
+			analysis := utils.DetectAccessibilityIssues(context.Background(), doc)
+
+			if analysis.Lang != tt.expectedLang {
+				t.Errorf("Lang = %v, want %v", analysis.Lang, tt.expectedLang)
+			}
+
+			paths := make(map[string][]string)
+			for _, issue := range analysis.Issues {
+				paths[issue.Code] = append(paths[issue.Code], issue.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.expectedPaths) {
+				t.Errorf("Issue paths = %v, want %v", paths, tt.expectedPaths)
+			}
+
+			for code, codePaths := range tt.expectedPaths {
+				if analysis.Summary[code] != len(codePaths) {
+					t.Errorf("Summary[%v] = %v, want %v", code, analysis.Summary[code], len(codePaths))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/PuerkitoBio/goquery"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// identity providers recognized in the text of the sso buttons, in the order they are matched
+var ssoProviders = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{name: "Google", pattern: regexp.MustCompile(`(?i)\bgoogle\b`)},
+	{name: "Microsoft", pattern: regexp.MustCompile(`(?i)\b(?:microsoft|azure|office\s*365|outlook)\b`)},
+	{name: "GitHub", pattern: regexp.MustCompile(`(?i)\bgithub\b`)},
+	{name: "Apple", pattern: regexp.MustCompile(`(?i)\bapple\b`)},
+	{name: "Facebook", pattern: regexp.MustCompile(`(?i)\bfacebook\b`)},
+	{name: "LinkedIn", pattern: regexp.MustCompile(`(?i)\blinkedin\b`)},
+	{name: "GitLab", pattern: regexp.MustCompile(`(?i)\bgitlab\b`)},
+	{name: "Okta", pattern: regexp.MustCompile(`(?i)\bokta\b`)},
+	{name: "X", pattern: regexp.MustCompile(`(?i)\b(?:twitter|x)\b`)},
+}
+
+// hosts of the authorization endpoints of the identity providers
+var oauthProviderHosts = map[string]string{
+	"accounts.google.com":       "Google",
+	"login.microsoftonline.com": "Microsoft",
+	"login.live.com":            "Microsoft",
+	"github.com":                "GitHub",
+	"appleid.apple.com":         "Apple",
+	"facebook.com":              "Facebook",
+	"linkedin.com":              "LinkedIn",
+	"gitlab.com":                "GitLab",
+	"okta.com":                  "Okta",
+	"auth0.com":                 "Auth0",
+	"twitter.com":               "X",
+	"x.com":                     "X",
+}
+
+// captcha widgets with the class of their container and the pattern of their script url
+var captchaWidgets = []struct {
+	provider      string
+	selector      string
+	scriptPattern *regexp.Regexp
+}{
+	{provider: "reCAPTCHA", selector: ".g-recaptcha", scriptPattern: regexp.MustCompile(`(?:google\.com|recaptcha\.net)/recaptcha/`)},
+	{provider: "hCaptcha", selector: ".h-captcha", scriptPattern: regexp.MustCompile(`(?:js\.)?hcaptcha\.com/1/api\.js`)},
+	{provider: "Turnstile", selector: ".cf-turnstile", scriptPattern: regexp.MustCompile(`challenges\.cloudflare\.com/turnstile/`)},
+}
+
+var (
+	ssoButtonRegex      = regexp.MustCompile(`(?i)(?:(?:sign|log)\s*(?:in|on|up)\s+(?:with|using|via|through)|continue\s+with)`)
+	oauthAuthorizeRegex = regexp.MustCompile(`(?i)(?:/authori[sz]e|/(?:oauth2?|openid-connect)(?:/[^/]+)*/auth)$`)
+	samlPathRegex       = regexp.MustCompile(`(?i)/(?:saml2?|adfs/ls|sso/saml)(?:/|$)`)
+	identifierNameRegex = regexp.MustCompile(`(?i)^(?:user(?:name|_?id)?|login|e-?mail|identifier|account|loginfmt|login_hint)$`)
+	signInRegex         = regexp.MustCompile(`(?i)(?:sign[\s_-]?in|log[\s_-]?in)`)
+	nextStepRegex       = regexp.MustCompile(`(?i)\b(?:next|continue)\b`)
+)
+
+// DetectAuthentication - detects the authentication surface of the web page
+// - forms with a password field
+// - identifier-first login forms which ask for the username or email before the password
+// - "sign in with" buttons of identity providers
+// - OAuth / OpenID Connect authorization urls and SAML endpoints in links and form actions
+// - reCAPTCHA, hCaptcha and Turnstile widgets
+// - one-time-code inputs used by multi factor authentication
+// LoginDetected is set when any of these, except the captchas, are found
+func (w *webAnalyzerUtilsImpl) DetectAuthentication(ctx context.Context, doc *goquery.Document, pageURL *url.URL) response_dtos.AuthenticationAnalysis {
+	auth := response_dtos.AuthenticationAnalysis{
+		SSOButtons:        []response_dtos.SSOButton{},
+		OAuthEndpoints:    []response_dtos.OAuthEndpoint{},
+		SAMLEndpoints:     []response_dtos.AuthEndpoint{},
+		Captchas:          []response_dtos.Captcha{},
+		OneTimeCodeFields: []string{},
+	}
+
+	doc.Find("input").Each(func(i int, s *goquery.Selection) {
+		if formFieldType(s) == "password" {
+			auth.PasswordForm = true
+		}
+		if hasAutocompleteToken(strings.ToLower(s.AttrOr("autocomplete", "")), "one-time-code") {
+			auth.OneTimeCodeFields = append(auth.OneTimeCodeFields, elementPath(s))
+		}
+	})
+
+	doc.Find("form").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		auth.IdentifierFirst = isIdentifierFirstForm(s)
+		return !auth.IdentifierFirst
+	})
+
+	baseURL := DocumentBaseURL(doc, pageURL)
+	w.detectAuthEndpoints(doc, baseURL, &auth)
+	w.detectSSOButtons(doc, baseURL, &auth)
+	detectCaptchas(doc, &auth)
+
+	auth.LoginDetected = auth.PasswordForm || auth.IdentifierFirst || len(auth.SSOButtons) > 0 || len(auth.OAuthEndpoints) > 0 ||
+		len(auth.SAMLEndpoints) > 0 || len(auth.OneTimeCodeFields) > 0
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified the document containing a login as %v with %v sso buttons", auth.LoginDetected, len(auth.SSOButtons)), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+
+	return auth
+}
+
+// detectAuthEndpoints - finds the OAuth authorization urls and the SAML endpoints in the links and form actions.
+// every url is reported once, with the path of the first element it was found in
+func (w *webAnalyzerUtilsImpl) detectAuthEndpoints(doc *goquery.Document, baseURL *url.URL, auth *response_dtos.AuthenticationAnalysis) {
+	seen := make(map[string]bool)
+
+	doc.Find("a[href], form").Each(func(i int, s *goquery.Selection) {
+		reference := s.AttrOr("href", "")
+		if goquery.NodeName(s) == "form" {
+			reference = s.AttrOr("action", "")
+		}
+		endpointURL, err := resolveReference(baseURL, reference)
+		if err != nil {
+			return
+		}
+		if seen[endpointURL.String()] {
+			return
+		}
+
+		if endpoint, ok := oauthEndpoint(endpointURL); ok {
+			seen[endpointURL.String()] = true
+			endpoint.Path = elementPath(s)
+			auth.OAuthEndpoints = append(auth.OAuthEndpoints, endpoint)
+			return
+		}
+
+		isSAMLForm := s.Find(`input[name="SAMLRequest"], input[name="SAMLResponse"]`).Length() > 0
+		if isSAMLForm || endpointURL.Query().Has("SAMLRequest") || samlPathRegex.MatchString(endpointURL.Path) {
+			seen[endpointURL.String()] = true
+			auth.SAMLEndpoints = append(auth.SAMLEndpoints, response_dtos.AuthEndpoint{Url: endpointURL.String(), Path: elementPath(s)})
+		}
+	})
+}
+
+// detectSSOButtons - finds the links and buttons with a "sign in with" or "continue with" text and the links to the
+// authorization endpoint of a known identity provider
+func (w *webAnalyzerUtilsImpl) detectSSOButtons(doc *goquery.Document, baseURL *url.URL, auth *response_dtos.AuthenticationAnalysis) {
+	doc.Find(`a, button, input[type="submit"], input[type="button"], [role="button"]`).Each(func(i int, s *goquery.Selection) {
+		text := strings.Join(strings.Fields(accessibleName(doc, s)), " ")
+
+		button := response_dtos.SSOButton{Text: text, Path: elementPath(s)}
+		if href, exists := s.Attr("href"); exists {
+			if buttonURL, err := resolveReference(baseURL, href); err == nil {
+				button.Url = buttonURL.String()
+			}
+		}
+
+		if ssoButtonRegex.MatchString(text) {
+			button.Provider = "other"
+			for _, provider := range ssoProviders {
+				if provider.pattern.MatchString(text) {
+					button.Provider = provider.name
+					break
+				}
+			}
+		} else if buttonURL, err := url.Parse(button.Url); button.Url != "" && err == nil {
+			if endpoint, ok := oauthEndpoint(buttonURL); ok && endpoint.Provider != "" {
+				button.Provider = endpoint.Provider
+			}
+		}
+
+		if button.Provider != "" {
+			auth.SSOButtons = append(auth.SSOButtons, button)
+		}
+	})
+}
+
+// detectCaptchas - finds the captcha widgets by their container. when a captcha has no container, such as the
+// invisible reCAPTCHA v3, it is detected by its script and the site key is taken from the render parameter
+func detectCaptchas(doc *goquery.Document, auth *response_dtos.AuthenticationAnalysis) {
+	for _, widget := range captchaWidgets {
+		found := false
+		doc.Find(widget.selector).Each(func(i int, s *goquery.Selection) {
+			found = true
+			auth.Captchas = append(auth.Captchas, response_dtos.Captcha{
+				Provider: widget.provider,
+				SiteKey:  strings.TrimSpace(s.AttrOr("data-sitekey", "")),
+				Path:     elementPath(s),
+			})
+		})
+		if found {
+			continue
+		}
+
+		doc.Find("script[src]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+			src := strings.TrimSpace(s.AttrOr("src", ""))
+			if !widget.scriptPattern.MatchString(src) {
+				return true
+			}
+
+			captcha := response_dtos.Captcha{Provider: widget.provider, Path: elementPath(s)}
+			if scriptURL, err := url.Parse(src); err == nil {
+				if render := scriptURL.Query().Get("render"); render != "explicit" && render != "onload" {
+					captcha.SiteKey = render
+				}
+			}
+			auth.Captchas = append(auth.Captchas, captcha)
+			return false
+		})
+	}
+}
+
+// oauthEndpoint - checks if the url is an OAuth authorization request, which has a client_id and a response_type
+// or an authorize path with one of them, and extracts its parameters
+func oauthEndpoint(endpointURL *url.URL) (response_dtos.OAuthEndpoint, bool) {
+	if endpointURL.Scheme != "http" && endpointURL.Scheme != "https" {
+		return response_dtos.OAuthEndpoint{}, false
+	}
+
+	query := endpointURL.Query()
+	hasClientId, hasResponseType := query.Has("client_id"), query.Has("response_type")
+	isAuthorizePath := oauthAuthorizeRegex.MatchString(endpointURL.Path)
+	if !(hasClientId && hasResponseType) && !(isAuthorizePath && (hasClientId || hasResponseType)) {
+		return response_dtos.OAuthEndpoint{}, false
+	}
+
+	endpoint := response_dtos.OAuthEndpoint{
+		Url:          endpointURL.String(),
+		Provider:     lookupDomain(oauthProviderHosts, strings.ToLower(endpointURL.Hostname())),
+		ClientId:     query.Get("client_id"),
+		ResponseType: query.Get("response_type"),
+		Scopes:       strings.Fields(strings.ReplaceAll(query.Get("scope"), ",", " ")),
+		PKCE:         query.Has("code_challenge"),
+	}
+	if endpoint.Scopes == nil {
+		endpoint.Scopes = []string{}
+	}
+	for _, scope := range endpoint.Scopes {
+		if scope == "openid" {
+			endpoint.OpenIDConnect = true
+		}
+	}
+	return endpoint, true
+}
+
+// isIdentifierFirstForm - checks if the form asks only for a username or an email, without a password, and
+// is a sign in form by the autocomplete hint of the field or the hints of the form. a next or continue button
+// is common to all multi step forms, so it is only accepted under a sign in heading
+func isIdentifierFirstForm(s *goquery.Selection) bool {
+	var textual []*goquery.Selection
+	hasPassword := false
+
+	s.Find("input, select, textarea").Each(func(i int, field *goquery.Selection) {
+		fieldType := formFieldType(field)
+		switch {
+		case fieldType == "password":
+			hasPassword = true
+		case !formButtonTypes[fieldType] && !nonTextualFieldTypes[fieldType]:
+			textual = append(textual, field)
+		}
+	})
+	if hasPassword || len(textual) != 1 {
+		return false
+	}
+
+	field := textual[0]
+	autocomplete := strings.ToLower(field.AttrOr("autocomplete", ""))
+	isIdentifier := formFieldType(field) == "email" || hasAutocompleteToken(autocomplete, "username") ||
+		hasAutocompleteToken(autocomplete, "email") || identifierNameRegex.MatchString(strings.TrimSpace(field.AttrOr("name", "")))
+	if !isIdentifier {
+		return false
+	}
+
+	hints := formHints(s)
+	if hasAutocompleteToken(autocomplete, "username") || hasAutocompleteToken(autocomplete, "webauthn") || signInRegex.MatchString(hints) {
+		return true
+	}
+
+	// the headings of the container of the form, which include the headings in the form
+	headings := s.Parent().Find("h1, h2, h3, h4, h5, h6").Text()
+	return nextStepRegex.MatchString(hints) && signInRegex.MatchString(headings)
+}
@@ -0,0 +1,152 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestDetectAuthentication(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+	config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1}
+	utils := NewWebAnalyzerUtils(logger, config)
+	pageURL, _ := url.Parse("https://example.com/login")
+
+	tests := []struct {
+		name     string
+		html     string
+		expected response_dtos.AuthenticationAnalysis
+	}{
+		{
+			name: "Identifier-first login with sso buttons",
+			html: `<html><body>
+				<form action="/login/identify" method="post">
+					<input type="email" name="email" autocomplete="username">
+					<button type="submit">Next</button>
+				</form>
+				<a href="https://accounts.google.com/o/oauth2/v2/auth?client_id=abc&response_type=code&scope=openid%20email&code_challenge=xyz">Sign in with Google</a>
+				<button>Continue with   Microsoft</button>
+				<a href="https://github.com/login/oauth/authorize?client_id=gh" aria-label="GitHub"></a>
+				</body></html>`,
+			expected: response_dtos.AuthenticationAnalysis{
+				LoginDetected:   true,
+				IdentifierFirst: true,
+				SSOButtons: []response_dtos.SSOButton{
+					{Provider: "Google", Text: "Sign in with Google", Url: "https://accounts.google.com/o/oauth2/v2/auth?client_id=abc&response_type=code&scope=openid%20email&code_challenge=xyz", Path: "html > body > a:nth-of-type(1)"},
+					{Provider: "Microsoft", Text: "Continue with Microsoft", Path: "html > body > button"},
+					{Provider: "GitHub", Text: "GitHub", Url: "https://github.com/login/oauth/authorize?client_id=gh", Path: "html > body > a:nth-of-type(2)"},
+				},
+				OAuthEndpoints: []response_dtos.OAuthEndpoint{
+					{Url: "https://accounts.google.com/o/oauth2/v2/auth?client_id=abc&response_type=code&scope=openid%20email&code_challenge=xyz", Provider: "Google", ClientId: "abc", ResponseType: "code", Scopes: []string{"openid", "email"}, OpenIDConnect: true, PKCE: true, Path: "html > body > a:nth-of-type(1)"},
+					{Url: "https://github.com/login/oauth/authorize?client_id=gh", Provider: "GitHub", ClientId: "gh", Scopes: []string{}, Path: "html > body > a:nth-of-type(2)"},
+				},
+				SAMLEndpoints:     []response_dtos.AuthEndpoint{},
+				Captchas:          []response_dtos.Captcha{},
+				OneTimeCodeFields: []string{},
+			},
+		},
+		{
+			name: "Password form with captcha and SAML",
+			html: `<html><head><script src="https://www.google.com/recaptcha/api.js?render=site-key-v3"></script></head><body>
+				<form action="/session" method="post">
+					<input name="user"><input type="password" name="pass">
+					<div class="h-captcha" data-sitekey="hc-key"></div>
+				</form>
+				<a href="/saml/login">Sign in with your company account</a>
+				</body></html>`,
+			expected: response_dtos.AuthenticationAnalysis{
+				LoginDetected: true,
+				PasswordForm:  true,
+				SSOButtons: []response_dtos.SSOButton{
+					{Provider: "other", Text: "Sign in with your company account", Url: "https://example.com/saml/login", Path: "html > body > a"},
+				},
+				OAuthEndpoints: []response_dtos.OAuthEndpoint{},
+				SAMLEndpoints: []response_dtos.AuthEndpoint{
+					{Url: "https://example.com/saml/login", Path: "html > body > a"},
+				},
+				Captchas: []response_dtos.Captcha{
+					{Provider: "reCAPTCHA", SiteKey: "site-key-v3", Path: "html > head > script"},
+					{Provider: "hCaptcha", SiteKey: "hc-key", Path: "html > body > form > div"},
+				},
+				OneTimeCodeFields: []string{},
+			},
+		},
+		{
+			name: "One-time-code step with turnstile",
+			html: `<html><body><form method="post">
+				<input name="code" inputmode="numeric" autocomplete="one-time-code">
+				<div class="cf-turnstile" data-sitekey="ts-key"></div>
+				</form></body></html>`,
+			expected: response_dtos.AuthenticationAnalysis{
+				LoginDetected:     true,
+				SSOButtons:        []response_dtos.SSOButton{},
+				OAuthEndpoints:    []response_dtos.OAuthEndpoint{},
+				SAMLEndpoints:     []response_dtos.AuthEndpoint{},
+				Captchas:          []response_dtos.Captcha{{Provider: "Turnstile", SiteKey: "ts-key", Path: "html > body > form > div"}},
+				OneTimeCodeFields: []string{"html > body > form > input"},
+			},
+		},
+		{
+			name: "Identifier-first step under a sign in heading",
+			html: `<html><body><main><h1>Sign in</h1>
+				<form action="/identify" method="post"><input type="email" name="identifier"><button>Next</button></form>
+				</main></body></html>`,
+			expected: response_dtos.AuthenticationAnalysis{
+				LoginDetected:     true,
+				IdentifierFirst:   true,
+				SSOButtons:        []response_dtos.SSOButton{},
+				OAuthEndpoints:    []response_dtos.OAuthEndpoint{},
+				SAMLEndpoints:     []response_dtos.AuthEndpoint{},
+				Captchas:          []response_dtos.Captcha{},
+				OneTimeCodeFields: []string{},
+			},
+		},
+		{
+			name: "Multi step signup form with continue is not a login",
+			html: `<html><body><main><h1>Create your account</h1>
+				<form action="/signup/step-1" method="post"><input type="email" name="email"><button>Continue</button></form>
+				</main></body></html>`,
+			expected: response_dtos.AuthenticationAnalysis{
+				SSOButtons:        []response_dtos.SSOButton{},
+				OAuthEndpoints:    []response_dtos.OAuthEndpoint{},
+				SAMLEndpoints:     []response_dtos.AuthEndpoint{},
+				Captchas:          []response_dtos.Captcha{},
+				OneTimeCodeFields: []string{},
+			},
+		},
+		{
+			name: "Newsletter form is not a login",
+			html: `<html><body><form class="newsletter"><input type="email" name="email"><button>Subscribe</button></form>
+				<a href="https://www.facebook.com/example">Follow us on Facebook</a></body></html>`,
+			expected: response_dtos.AuthenticationAnalysis{
+				SSOButtons:        []response_dtos.SSOButton{},
+				OAuthEndpoints:    []response_dtos.OAuthEndpoint{},
+				SAMLEndpoints:     []response_dtos.AuthEndpoint{},
+				Captchas:          []response_dtos.Captcha{},
+				OneTimeCodeFields: []string{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatalf("Failed to parse HTML: %v", err)
+			}
+
+			auth := utils.DetectAuthentication(context.Background(), doc, pageURL)
+
+			if !reflect.DeepEqual(auth, tt.expected) {
+				t.Errorf("DetectAuthentication() = %+v, want %+v", auth, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"net/http"
+	"regexp"
+)
+
+// authCookieNameRegex - names of cookies which usually hold a session or an authentication token
+var authCookieNameRegex = regexp.MustCompile(`(?i)(sess|(^|[_.-])sid($|[_.-])|auth|token|jwt|login|remember)`)
+
+// csrfCookieNameRegex - names of cookies which hold a csrf token, e.g. csrftoken and XSRF-TOKEN
+var csrfCookieNameRegex = regexp.MustCompile(`(?i)(csrf|xsrf)`)
+
+// DetectCookies - parses every Set-Cookie header of the response and reports the attributes of the cookies.
+// cookies which look like they hold a session or a token are flagged when they are missing Secure or
+// HttpOnly, and any cookie with SameSite=None is flagged when it is missing Secure. csrf token cookies are
+// read by scripts to be sent back in a header, so they are not flagged when they are missing HttpOnly
+func (w *webAnalyzerUtilsImpl) DetectCookies(ctx context.Context, header http.Header) response_dtos.CookieAnalysis {
+	analysis := response_dtos.CookieAnalysis{
+		Cookies: []response_dtos.CookieDetails{},
+		Issues:  []response_dtos.AnalysisIssue{},
+	}
+
+	for _, setCookie := range header.Values("Set-Cookie") {
+		cookie, err := http.ParseSetCookie(setCookie)
+		if err != nil {
+			analysis.Issues = append(analysis.Issues, newIssue("invalid_cookie", IssueSeverityWarning, fmt.Sprintf("the Set-Cookie header %q cannot be parsed: %v", setCookie, err)))
+			continue
+		}
+
+		details := response_dtos.CookieDetails{
+			Name:          cookie.Name,
+			Domain:        cookie.Domain,
+			Path:          cookie.Path,
+			MaxAge:        cookie.MaxAge,
+			SessionCookie: cookie.Expires.IsZero() && cookie.MaxAge == 0,
+			Secure:        cookie.Secure,
+			HttpOnly:      cookie.HttpOnly,
+			SameSite:      sameSiteName(cookie.SameSite),
+			Size:          len(cookie.Name) + len(cookie.Value),
+			LooksLikeAuth: authCookieNameRegex.MatchString(cookie.Name),
+		}
+		if !cookie.Expires.IsZero() {
+			details.Expires = &cookie.Expires
+		}
+		analysis.Cookies = append(analysis.Cookies, details)
+
+		if details.LooksLikeAuth && !details.Secure {
+			analysis.Issues = append(analysis.Issues, newIssue("auth_cookie_not_secure", IssueSeverityError, fmt.Sprintf("the cookie %v looks like a session cookie but is not Secure", cookie.Name)))
+		}
+		if details.LooksLikeAuth && !details.HttpOnly && !csrfCookieNameRegex.MatchString(cookie.Name) {
+			analysis.Issues = append(analysis.Issues, newIssue("auth_cookie_not_http_only", IssueSeverityWarning, fmt.Sprintf("the cookie %v looks like a session cookie but is readable by scripts", cookie.Name)))
+		}
+		if cookie.SameSite == http.SameSiteNoneMode && !details.Secure {
+			analysis.Issues = append(analysis.Issues, newIssue("same_site_none_not_secure", IssueSeverityError, fmt.Sprintf("the cookie %v sets SameSite=None without Secure and is rejected by browsers", cookie.Name)))
+		}
+	}
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified %v cookies with %v issues", len(analysis.Cookies), len(analysis.Issues)), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+
+	return analysis
+}
+
+func sameSiteName(sameSite http.SameSite) string {
+	switch sameSite {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	default:
+		return ""
+	}
+}
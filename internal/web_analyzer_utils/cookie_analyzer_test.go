@@ -0,0 +1,89 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+)
+
+func TestDetectCookies(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+	config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1}
+	utils := NewWebAnalyzerUtils(logger, config)
+
+	expires := time.Date(2030, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		setCookies         []string
+		expectedCookies    []response_dtos.CookieDetails
+		expectedIssueCodes []string
+	}{
+		{
+			name:               "No Cookies",
+			expectedCookies:    []response_dtos.CookieDetails{},
+			expectedIssueCodes: []string{},
+		},
+		{
+			name: "Secure Session Cookie",
+			setCookies: []string{
+				"PHPSESSID=abc123; Path=/; Secure; HttpOnly; SameSite=Lax",
+				"theme=dark; Domain=example.com; Expires=Wed, 02 Jan 2030 15:04:05 GMT",
+			},
+			expectedCookies: []response_dtos.CookieDetails{
+				{Name: "PHPSESSID", Path: "/", SessionCookie: true, Secure: true, HttpOnly: true, SameSite: "Lax", Size: 15, LooksLikeAuth: true},
+				{Name: "theme", Domain: "example.com", Expires: &expires, Size: 9},
+			},
+			expectedIssueCodes: []string{},
+		},
+		{
+			name: "Insecure Cookies",
+			setCookies: []string{
+				"auth_token=xyz; Max-Age=3600; SameSite=None",
+				"tracking=1; SameSite=None; Secure",
+				"=novalue",
+			},
+			expectedCookies: []response_dtos.CookieDetails{
+				{Name: "auth_token", MaxAge: 3600, SameSite: "None", Size: 13, LooksLikeAuth: true},
+				{Name: "tracking", Secure: true, SessionCookie: true, SameSite: "None", Size: 9},
+			},
+			expectedIssueCodes: []string{"auth_cookie_not_secure", "auth_cookie_not_http_only", "same_site_none_not_secure", "invalid_cookie"},
+		},
+		{
+			name: "CSRF Token Cookies Readable By Scripts",
+			setCookies: []string{
+				"csrftoken=abc; Path=/; Secure; SameSite=Lax",
+				"XSRF-TOKEN=def; Path=/; Secure",
+			},
+			expectedCookies: []response_dtos.CookieDetails{
+				{Name: "csrftoken", Path: "/", SessionCookie: true, Secure: true, SameSite: "Lax", Size: 12, LooksLikeAuth: true},
+				{Name: "XSRF-TOKEN", Path: "/", SessionCookie: true, Secure: true, Size: 13, LooksLikeAuth: true},
+			},
+			expectedIssueCodes: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for _, setCookie := range tt.setCookies {
+				header.Add("Set-Cookie", setCookie)
+			}
+
+			analysis := utils.DetectCookies(context.Background(), header)
+
+			if !reflect.DeepEqual(analysis.Cookies, tt.expectedCookies) {
+				t.Errorf("Cookies = %+v, want %+v", analysis.Cookies, tt.expectedCookies)
+			}
+			if codes := issueCodes(analysis.Issues); !reflect.DeepEqual(codes, tt.expectedIssueCodes) {
+				t.Errorf("Issues = %v, want %v", codes, tt.expectedIssueCodes)
+			}
+		})
+	}
+}
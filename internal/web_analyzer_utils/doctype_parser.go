@@ -0,0 +1,294 @@
+package web_analyzer_utils
+
+import (
+	"bytes"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"golang.org/x/net/html"
+	"strings"
+)
+
+// document modes computed from the doctype as defined by the html standard
+const (
+	DocumentModeQuirks        = "quirks"
+	DocumentModeLimitedQuirks = "limited-quirks"
+	DocumentModeNoQuirks      = "no-quirks"
+)
+
+const (
+	htmlVersionUnknown   = "Unknown"
+	htmlVersionCustomDTD = "Custom DTD"
+)
+
+const doctypeWhitespace = " \t\n\f\r"
+
+// htmlVersionsByPublicId - html versions identified by the prefix of the lower cased public identifier.
+// longer prefixes of the same family are listed first
+var htmlVersionsByPublicId = []struct {
+	prefix  string
+	version string
+}{
+	{"-//w3c//dtd html 4.01 frameset//", "HTML 4.01 Frameset"},
+	{"-//w3c//dtd html 4.01 transitional//", "HTML 4.01 Transitional"},
+	{"-//w3c//dtd html 4.01//", "HTML 4.01 Strict"},
+	{"-//w3c//dtd html 4.0 frameset//", "HTML 4.0 Frameset"},
+	{"-//w3c//dtd html 4.0 transitional//", "HTML 4.0 Transitional"},
+	{"-//w3c//dtd html 4.0//", "HTML 4.0 Strict"},
+	{"-//w3c//dtd xhtml 1.1//", "XHTML 1.1"},
+	{"-//w3c//dtd xhtml 1.0 frameset//", "XHTML 1.0 Frameset"},
+	{"-//w3c//dtd xhtml 1.0 transitional//", "XHTML 1.0 Transitional"},
+	{"-//w3c//dtd xhtml 1.0 strict//", "XHTML 1.0 Strict"},
+	{"-//w3c//dtd xhtml basic 1.1//", "XHTML Basic 1.1"},
+	{"-//w3c//dtd xhtml basic 1.0//", "XHTML Basic 1.0"},
+	{"-//w3c//dtd xhtml+rdfa 1.1//", "XHTML+RDFa 1.1"},
+	{"-//w3c//dtd xhtml+rdfa 1.0//", "XHTML+RDFa 1.0"},
+	{"-//w3c//dtd html 3.2", "HTML 3.2"},
+	{"-//ietf//dtd html 3.2", "HTML 3.2"},
+	{"-//ietf//dtd html 2.0", "HTML 2.0"},
+	{"-//ietf//dtd html//", "HTML 2.0"},
+}
+
+// quirkyPublicIdPrefixes - public identifier prefixes which put the document in quirks mode
+var quirkyPublicIdPrefixes = []string{
+	"+//silmaril//dtd html pro v0r11 19970101//",
+	"-//as//dtd html 3.0 aswedit + extensions//",
+	"-//advasoft ltd//dtd html 3.0 aswedit + extensions//",
+	"-//ietf//dtd html 2.0 level 1//",
+	"-//ietf//dtd html 2.0 level 2//",
+	"-//ietf//dtd html 2.0 strict level 1//",
+	"-//ietf//dtd html 2.0 strict level 2//",
+	"-//ietf//dtd html 2.0 strict//",
+	"-//ietf//dtd html 2.0//",
+	"-//ietf//dtd html 2.1e//",
+	"-//ietf//dtd html 3.0//",
+	"-//ietf//dtd html 3.2 final//",
+	"-//ietf//dtd html 3.2//",
+	"-//ietf//dtd html 3//",
+	"-//ietf//dtd html level 0//",
+	"-//ietf//dtd html level 1//",
+	"-//ietf//dtd html level 2//",
+	"-//ietf//dtd html level 3//",
+	"-//ietf//dtd html strict level 0//",
+	"-//ietf//dtd html strict level 1//",
+	"-//ietf//dtd html strict level 2//",
+	"-//ietf//dtd html strict level 3//",
+	"-//ietf//dtd html strict//",
+	"-//ietf//dtd html//",
+	"-//metrius//dtd metrius presentational//",
+	"-//microsoft//dtd internet explorer 2.0 html strict//",
+	"-//microsoft//dtd internet explorer 2.0 html//",
+	"-//microsoft//dtd internet explorer 2.0 tables//",
+	"-//microsoft//dtd internet explorer 3.0 html strict//",
+	"-//microsoft//dtd internet explorer 3.0 html//",
+	"-//microsoft//dtd internet explorer 3.0 tables//",
+	"-//netscape comm. corp.//dtd html//",
+	"-//netscape comm. corp.//dtd strict html//",
+	"-//o'reilly and associates//dtd html 2.0//",
+	"-//o'reilly and associates//dtd html extended 1.0//",
+	"-//o'reilly and associates//dtd html extended relaxed 1.0//",
+	"-//sq//dtd html 2.0 hotmetal + extensions//",
+	"-//softquad software//dtd hotmetal pro 6.0::19990601::extensions to html 4.0//",
+	"-//softquad//dtd hotmetal pro 4.0::19971010::extensions to html 4.0//",
+	"-//spyglass//dtd html 2.0 extended//",
+	"-//sun microsystems corp.//dtd hotjava html//",
+	"-//sun microsystems corp.//dtd hotjava strict html//",
+	"-//w3c//dtd html 3 1995-03-24//",
+	"-//w3c//dtd html 3.2 draft//",
+	"-//w3c//dtd html 3.2 final//",
+	"-//w3c//dtd html 3.2//",
+	"-//w3c//dtd html 3.2s draft//",
+	"-//w3c//dtd html 4.0 frameset//",
+	"-//w3c//dtd html 4.0 transitional//",
+	"-//w3c//dtd html experimental 19960712//",
+	"-//w3c//dtd html experimental 970421//",
+	"-//w3c//dtd w3 html//",
+	"-//w3o//dtd w3 html 3.0//",
+	"-//webtechs//dtd mozilla html 2.0//",
+	"-//webtechs//dtd mozilla html//",
+}
+
+// utf8ByteOrderMark - byte order mark many pages start with, which the tokenizer would read as text
+var utf8ByteOrderMark = []byte("\xEF\xBB\xBF")
+
+// parsedDoctype - doctype token split into its parts. forceQuirks is set when the
+// identifiers are malformed
+type parsedDoctype struct {
+	name        string
+	publicId    *string
+	systemId    *string
+	forceQuirks bool
+}
+
+// parseDoctype - tokenizes the raw document and parses the doctype which precedes the first element.
+// a leading utf-8 byte order mark is skipped and a doctype like text found after the document has started is ignored
+func parseDoctype(body []byte) response_dtos.Doctype {
+	doctype := response_dtos.Doctype{
+		Version: htmlVersionUnknown,
+		Mode:    DocumentModeQuirks,
+	}
+
+	body = bytes.TrimPrefix(body, utf8ByteOrderMark)
+
+	tokenizer := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		switch tokenizer.Next() {
+		case html.DoctypeToken:
+			data := string(tokenizer.Text())
+			parsed := splitDoctype(data)
+
+			doctype.Present = true
+			doctype.Raw = "<!DOCTYPE " + data + ">"
+			doctype.Name = parsed.name
+			if parsed.publicId != nil {
+				doctype.PublicId = *parsed.publicId
+			}
+			if parsed.systemId != nil {
+				doctype.SystemId = *parsed.systemId
+			}
+			doctype.Version = doctypeVersion(parsed)
+			doctype.Mode = doctypeMode(parsed)
+			return doctype
+		case html.CommentToken:
+			continue
+		case html.TextToken:
+			if len(bytes.Trim(tokenizer.Text(), doctypeWhitespace)) == 0 {
+				continue
+			}
+			return doctype
+		default: // the document started, or ended, without a doctype
+			return doctype
+		}
+	}
+}
+
+// splitDoctype - splits the doctype token data, e.g. html PUBLIC "-//W3C//DTD HTML 4.01//EN" "http://...",
+// into the name, the public identifier and the system identifier
+func splitDoctype(data string) parsedDoctype {
+	var parsed parsedDoctype
+
+	data = strings.TrimLeft(data, doctypeWhitespace)
+	nameEnd := strings.IndexAny(data, doctypeWhitespace)
+	if nameEnd == -1 {
+		nameEnd = len(data)
+	}
+	parsed.name = strings.ToLower(data[:nameEnd])
+	rest := strings.TrimLeft(data[nameEnd:], doctypeWhitespace)
+
+	if rest == "" {
+		return parsed
+	}
+	if len(rest) < 6 {
+		parsed.forceQuirks = true
+		return parsed
+	}
+
+	keyword := strings.ToLower(rest[:6])
+	rest = rest[6:]
+
+	var identifiers []*string
+	switch keyword {
+	case "public":
+		identifiers = []*string{new(string), new(string)}
+	case "system":
+		identifiers = []*string{new(string)}
+	default:
+		parsed.forceQuirks = true
+		return parsed
+	}
+
+	found := 0
+	for _, identifier := range identifiers {
+		rest = strings.TrimLeft(rest, doctypeWhitespace)
+		if rest == "" || (rest[0] != '"' && rest[0] != '\'') {
+			break
+		}
+		end := strings.IndexByte(rest[1:], rest[0])
+		if end == -1 { // an unterminated identifier forces quirks mode
+			*identifier = rest[1:]
+			parsed.forceQuirks = true
+			rest = ""
+		} else {
+			*identifier = rest[1 : end+1]
+			rest = rest[end+2:]
+		}
+		found++
+	}
+
+	if found == 0 || strings.TrimLeft(rest, doctypeWhitespace) != "" {
+		parsed.forceQuirks = true
+	}
+
+	if keyword == "public" {
+		if found >= 1 {
+			parsed.publicId = identifiers[0]
+		}
+		if found == 2 {
+			parsed.systemId = identifiers[1]
+		}
+	} else if found == 1 {
+		parsed.systemId = identifiers[0]
+	}
+
+	return parsed
+}
+
+// doctypeVersion - identifies the html version from the doctype name and identifiers
+func doctypeVersion(parsed parsedDoctype) string {
+	if parsed.publicId == nil {
+		if parsed.name != "html" || (parsed.forceQuirks && parsed.systemId == nil) {
+			return htmlVersionUnknown
+		}
+		if parsed.systemId == nil || strings.EqualFold(*parsed.systemId, "about:legacy-compat") {
+			return "HTML 5"
+		}
+		return htmlVersionCustomDTD
+	}
+
+	publicId := strings.ToLower(*parsed.publicId)
+	for _, known := range htmlVersionsByPublicId {
+		if strings.HasPrefix(publicId, known.prefix) {
+			return known.version
+		}
+	}
+	return htmlVersionCustomDTD
+}
+
+// doctypeMode - computes the quirks, limited-quirks or no-quirks mode the browsers render the document in
+func doctypeMode(parsed parsedDoctype) string {
+	if parsed.forceQuirks || parsed.name != "html" {
+		return DocumentModeQuirks
+	}
+
+	publicId, systemId := "", ""
+	if parsed.publicId != nil {
+		publicId = strings.ToLower(*parsed.publicId)
+	}
+	if parsed.systemId != nil {
+		systemId = strings.ToLower(*parsed.systemId)
+	}
+
+	switch publicId {
+	case "-//w3o//dtd w3 html strict 3.0//en//", "-/w3c/dtd html 4.0 transitional/en", "html":
+		return DocumentModeQuirks
+	}
+	if systemId == "http://www.ibm.com/data/dtd/v11/ibmxhtml1-transitional.dtd" {
+		return DocumentModeQuirks
+	}
+	for _, prefix := range quirkyPublicIdPrefixes {
+		if strings.HasPrefix(publicId, prefix) {
+			return DocumentModeQuirks
+		}
+	}
+
+	isHTML401FramesetOrTransitional := strings.HasPrefix(publicId, "-//w3c//dtd html 4.01 frameset//") ||
+		strings.HasPrefix(publicId, "-//w3c//dtd html 4.01 transitional//")
+	if isHTML401FramesetOrTransitional && parsed.systemId == nil {
+		return DocumentModeQuirks
+	}
+	if isHTML401FramesetOrTransitional ||
+		strings.HasPrefix(publicId, "-//w3c//dtd xhtml 1.0 frameset//") ||
+		strings.HasPrefix(publicId, "-//w3c//dtd xhtml 1.0 transitional//") {
+		return DocumentModeLimitedQuirks
+	}
+
+	return DocumentModeNoQuirks
+}
@@ -0,0 +1,239 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/PuerkitoBio/goquery"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// classifications of the forms
+const (
+	FormTypeLogin         = "login"
+	FormTypeSignup        = "signup"
+	FormTypeSearch        = "search"
+	FormTypeNewsletter    = "newsletter"
+	FormTypePayment       = "payment"
+	FormTypeContact       = "contact"
+	FormTypePasswordReset = "password_reset"
+	FormTypeOther         = "other"
+)
+
+// input types which submit or reset the form instead of holding a value
+var formButtonTypes = map[string]bool{
+	"submit": true,
+	"reset":  true,
+	"button": true,
+	"image":  true,
+}
+
+// input types which are not filled in by typing
+var nonTextualFieldTypes = map[string]bool{
+	"hidden":   true,
+	"checkbox": true,
+	"radio":    true,
+	"file":     true,
+}
+
+var searchFieldNames = map[string]bool{
+	"q":        true,
+	"query":    true,
+	"s":        true,
+	"search":   true,
+	"keyword":  true,
+	"keywords": true,
+}
+
+// hints found in the attributes, field names and buttons of a form
+var (
+	paymentFieldRegex   = regexp.MustCompile(`(?i)(?:card[\s_-]?(?:number|num|no)|cc[\s_-]?(?:number|num|no)|cvc|cvv|security[\s_-]?code|expir)`)
+	signupHintRegex     = regexp.MustCompile(`(?i)(?:register|sign[\s_-]?up|create[\s_-]?(?:an[\s_-]?)?account|join)`)
+	passwordResetRegex  = regexp.MustCompile(`(?i)(?:forgot|reset|recover|lost[\s_-]?password)`)
+	newsletterHintRegex = regexp.MustCompile(`(?i)(?:newsletter|subscribe|mailing[\s_-]?list)`)
+	contactHintRegex    = regexp.MustCompile(`(?i)(?:contact|enquir|inquir|message|feedback)`)
+)
+
+// DetectForms - reports every form of the web page with its method, action resolved against the page url and fields,
+// and classifies it as a login, signup, search, newsletter, payment, contact or password reset form.
+// the following problems are reported
+// - forms with password or payment fields submitted over http or to another registrable domain
+// - password fields without a current-password or new-password autocomplete hint
+func (w *webAnalyzerUtilsImpl) DetectForms(ctx context.Context, doc *goquery.Document, pageURL *url.URL) response_dtos.FormAnalysis {
+	analysis := response_dtos.FormAnalysis{
+		Forms:  []response_dtos.Form{},
+		Counts: make(map[string]int),
+		Issues: []response_dtos.AnalysisIssue{},
+	}
+	baseURL := DocumentBaseURL(doc, pageURL)
+
+	doc.Find("form").Each(func(i int, s *goquery.Selection) {
+		form := response_dtos.Form{
+			Method: formMethod(s),
+			Fields: []response_dtos.FormField{},
+			Path:   elementPath(s),
+		}
+
+		actionURL := pageURL // forms without an action are submitted to the page itself, not to the base url
+		if action := strings.TrimSpace(s.AttrOr("action", "")); action != "" {
+			if resolvedURL, err := resolveReference(baseURL, action); err == nil {
+				actionURL = resolvedURL
+			}
+		}
+		form.Action = actionURL.String()
+
+		passwordFields := 0
+		s.Find("input, select, textarea").Each(func(j int, field *goquery.Selection) {
+			formField := response_dtos.FormField{
+				Name:         strings.TrimSpace(field.AttrOr("name", "")),
+				Type:         formFieldType(field),
+				Required:     hasAttr(field, "required"),
+				Autocomplete: strings.ToLower(strings.TrimSpace(field.AttrOr("autocomplete", ""))),
+			}
+			if formButtonTypes[formField.Type] {
+				return
+			}
+			form.Fields = append(form.Fields, formField)
+
+			if formField.Type != "password" {
+				return
+			}
+			passwordFields++
+			if !hasAutocompleteToken(formField.Autocomplete, "current-password") && !hasAutocompleteToken(formField.Autocomplete, "new-password") {
+				analysis.Issues = append(analysis.Issues, newElementIssue("password_missing_autocomplete", IssueSeverityWarning, fmt.Sprintf("the password field %q has no current-password or new-password autocomplete hint", formField.Name), elementPath(field)))
+			}
+		})
+
+		form.Classification = classifyForm(s, form.Fields)
+		analysis.Counts[form.Classification]++
+
+		if passwordFields > 0 || form.Classification == FormTypePayment {
+			switch {
+			case actionURL.Scheme == "http":
+				analysis.Issues = append(analysis.Issues, newElementIssue("credentials_over_http", IssueSeverityError, fmt.Sprintf("the %v form submits credentials over http to %v", form.Classification, form.Action), form.Path))
+			case actionURL.Scheme == "https" && registrableDomain(actionURL.Hostname()) != registrableDomain(pageURL.Hostname()):
+				analysis.Issues = append(analysis.Issues, newElementIssue("credentials_to_third_party", IssueSeverityWarning, fmt.Sprintf("the %v form submits credentials to the third-party origin %v", form.Classification, actionURL.Host), form.Path))
+			}
+		}
+
+		analysis.Forms = append(analysis.Forms, form)
+	})
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified %v forms with %v issues", len(analysis.Forms), len(analysis.Issues)), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+
+	return analysis
+}
+
+// classifyForm - classifies the form by its fields and by the hints found in its attributes, field names and buttons
+func classifyForm(s *goquery.Selection, fields []response_dtos.FormField) string {
+	hints := formHints(s)
+
+	var passwords, newPasswords, identifiers, emails, textareas, textual int
+	isSearch := strings.EqualFold(s.AttrOr("role", ""), "search")
+	isPayment := false
+
+	for _, field := range fields {
+		name := strings.ToLower(field.Name)
+		if !nonTextualFieldTypes[field.Type] {
+			textual++
+		}
+		if strings.Contains(" "+field.Autocomplete, " cc-") || paymentFieldRegex.MatchString(name) {
+			isPayment = true
+		}
+
+		switch {
+		case field.Type == "password":
+			passwords++
+			if hasAutocompleteToken(field.Autocomplete, "new-password") {
+				newPasswords++
+			}
+		case field.Type == "search" || searchFieldNames[name]:
+			isSearch = true
+		case field.Type == "textarea":
+			textareas++
+		case field.Type == "email" || strings.Contains(name, "email") || hasAutocompleteToken(field.Autocomplete, "email"):
+			emails++
+			identifiers++
+		case field.Type == "text" || field.Type == "tel":
+			identifiers++
+		}
+	}
+
+	switch {
+	case isPayment:
+		return FormTypePayment
+	case passwords > 0:
+		if newPasswords > 0 || passwords > 1 {
+			if identifiers == 0 || passwordResetRegex.MatchString(hints) {
+				return FormTypePasswordReset
+			}
+			return FormTypeSignup
+		}
+		if signupHintRegex.MatchString(hints) {
+			return FormTypeSignup
+		}
+		return FormTypeLogin
+	case isSearch:
+		return FormTypeSearch
+	case passwordResetRegex.MatchString(hints):
+		return FormTypePasswordReset
+	case signupHintRegex.MatchString(hints):
+		return FormTypeSignup
+	case newsletterHintRegex.MatchString(hints) && emails > 0:
+		return FormTypeNewsletter
+	case textareas > 0 || contactHintRegex.MatchString(hints):
+		return FormTypeContact
+	case emails > 0 && emails == textual:
+		return FormTypeNewsletter
+	}
+	return FormTypeOther
+}
+
+// formHints - joins the id, class, name, action and aria-label of the form with its field names and button texts
+func formHints(s *goquery.Selection) string {
+	var hints []string
+	for _, attr := range []string{"id", "class", "name", "action", "aria-label"} {
+		hints = append(hints, s.AttrOr(attr, ""))
+	}
+	s.Find("input, select, textarea").Each(func(i int, field *goquery.Selection) {
+		hints = append(hints, field.AttrOr("name", ""), field.AttrOr("id", ""))
+	})
+	s.Find(`button, input[type="submit"], legend`).Each(func(i int, button *goquery.Selection) {
+		hints = append(hints, button.Text(), button.AttrOr("value", ""))
+	})
+	return strings.Join(hints, " ")
+}
+
+// formMethod - returns the upper case method of the form. missing and invalid methods fall back to GET
+func formMethod(s *goquery.Selection) string {
+	method := strings.ToUpper(strings.TrimSpace(s.AttrOr("method", "")))
+	if method == "POST" || method == "DIALOG" {
+		return method
+	}
+	return "GET"
+}
+
+// formFieldType - returns the lower case input type, or the element name for selects and textareas
+func formFieldType(field *goquery.Selection) string {
+	if goquery.NodeName(field) != "input" {
+		return goquery.NodeName(field)
+	}
+	inputType := strings.ToLower(strings.TrimSpace(field.AttrOr("type", "")))
+	if inputType == "" {
+		return "text"
+	}
+	return inputType
+}
+
+// hasAutocompleteToken - checks if the space separated autocomplete value contains the given token
+func hasAutocompleteToken(autocomplete string, token string) bool {
+	for _, value := range strings.Fields(autocomplete) {
+		if value == token {
+			return true
+		}
+	}
+	return false
+}
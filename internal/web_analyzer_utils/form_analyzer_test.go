@@ -0,0 +1,145 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestDetectForms(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+	config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1}
+	utils := NewWebAnalyzerUtils(logger, config)
+
+	tests := []struct {
+		name                   string
+		pageURL                string
+		html                   string
+		expectedForms          []response_dtos.Form
+		expectedClassification []string
+		expectedIssues         []string
+	}{
+		{
+			name:    "Login form with autocomplete hints",
+			pageURL: "https://example.com/account/",
+			html: `<form action="login" method="post">
+				<input type="email" name="email" autocomplete="username" required>
+				<input type="password" name="password" autocomplete="current-password" required>
+				<input type="hidden" name="csrf" value="1">
+				<button type="submit">Sign in</button>
+				</form>`,
+			expectedForms: []response_dtos.Form{
+				{
+					Classification: "login",
+					Method:         "POST",
+					Action:         "https://example.com/account/login",
+					Fields: []response_dtos.FormField{
+						{Name: "email", Type: "email", Required: true, Autocomplete: "username"},
+						{Name: "password", Type: "password", Required: true, Autocomplete: "current-password"},
+						{Name: "csrf", Type: "hidden"},
+					},
+					Path: "html > body > form",
+				},
+			},
+			expectedClassification: []string{"login"},
+			expectedIssues:         []string{},
+		},
+		{
+			name:    "Search form with default method and action",
+			pageURL: "https://example.com/docs?page=2",
+			html:    `<form role="search"><input type="text" name="q"><input type="submit" value="Go"></form>`,
+			expectedForms: []response_dtos.Form{
+				{
+					Classification: "search",
+					Method:         "GET",
+					Action:         "https://example.com/docs?page=2",
+					Fields:         []response_dtos.FormField{{Name: "q", Type: "text"}},
+					Path:           "html > body > form",
+				},
+			},
+			expectedClassification: []string{"search"},
+			expectedIssues:         []string{},
+		},
+		{
+			name:    "Action resolved against base href",
+			pageURL: "https://example.com/app/",
+			html:    `<html><head><base href="https://example.com/api/"></head><body><form action="subscribe"><input type="email" name="email"></form><form><input type="search" name="q"></form></body></html>`,
+			expectedForms: []response_dtos.Form{
+				{Classification: "newsletter", Method: "GET", Action: "https://example.com/api/subscribe", Fields: []response_dtos.FormField{{Name: "email", Type: "email"}}, Path: "html > body > form:nth-of-type(1)"},
+				{Classification: "search", Method: "GET", Action: "https://example.com/app/", Fields: []response_dtos.FormField{{Name: "q", Type: "search"}}, Path: "html > body > form:nth-of-type(2)"},
+			},
+			expectedClassification: []string{"newsletter", "search"},
+			expectedIssues:         []string{},
+		},
+		{
+			name:                   "Login over http without autocomplete",
+			pageURL:                "http://example.com/",
+			html:                   `<form method="POST" action="/session"><input name="user"><input type="password" name="pass"></form>`,
+			expectedClassification: []string{"login"},
+			expectedIssues:         []string{"password_missing_autocomplete", "credentials_over_http"},
+		},
+		{
+			name:    "Login posted to a third-party origin",
+			pageURL: "https://www.example.com/",
+			html: `<form method="post" action="https://auth.example.com/login"><input name="user"><input type="password" name="pass" autocomplete="current-password"></form>
+				<form method="post" action="https://login.other.net/"><input name="user"><input type="password" name="pass" autocomplete="current-password"></form>`,
+			expectedClassification: []string{"login", "login"},
+			expectedIssues:         []string{"credentials_to_third_party"},
+		},
+		{
+			name:    "Signup, password reset and change password",
+			pageURL: "https://example.com/",
+			html: `<form id="register"><input type="email" name="email"><input type="password" name="password" autocomplete="new-password"><input type="password" name="confirm" autocomplete="new-password"></form>
+				<form action="/forgot-password"><input type="email" name="email"><button>Send reset link</button></form>
+				<form><input type="password" name="new" autocomplete="new-password"><input type="password" name="repeat" autocomplete="new-password"></form>`,
+			expectedClassification: []string{"signup", "password_reset", "password_reset"},
+			expectedIssues:         []string{},
+		},
+		{
+			name:    "Newsletter, contact and payment forms",
+			pageURL: "https://example.com/",
+			html: `<form class="newsletter"><input type="email" name="email"><input type="checkbox" name="consent"><button>Subscribe</button></form>
+				<form><input type="email" name="email"></form>
+				<form><input name="name"><input type="email" name="email"><textarea name="body"></textarea></form>
+				<form method="post" action="http://pay.example.com/charge"><input name="number" autocomplete="cc-number"><input name="cvc"></form>
+				<form><select name="country"></select></form>`,
+			expectedClassification: []string{"newsletter", "newsletter", "contact", "payment", "other"},
+			expectedIssues:         []string{"credentials_over_http"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatalf("Failed to parse HTML: %v", err)
+			}
+			pageURL, _ := url.Parse(tt.pageURL)
+
+			analysis := utils.DetectForms(context.Background(), doc, pageURL)
+
+			if tt.expectedForms != nil && !reflect.DeepEqual(analysis.Forms, tt.expectedForms) {
+				t.Errorf("Forms = %+v, want %+v", analysis.Forms, tt.expectedForms)
+			}
+
+			var classifications []string
+			for _, form := range analysis.Forms {
+				classifications = append(classifications, form.Classification)
+			}
+			if !reflect.DeepEqual(classifications, tt.expectedClassification) {
+				t.Errorf("Classifications = %v, want %v", classifications, tt.expectedClassification)
+			}
+
+			if codes := issueCodes(analysis.Issues); !reflect.DeepEqual(codes, tt.expectedIssues) {
+				t.Errorf("Issues = %v, want %v", codes, tt.expectedIssues)
+			}
+		})
+	}
+}
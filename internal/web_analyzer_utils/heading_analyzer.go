@@ -0,0 +1,81 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/PuerkitoBio/goquery"
+	"strings"
+)
+
+// DetectHeadingOutline - builds the outline of the h1 - h6 headings in document order. a heading becomes a
+// child of the closest preceding heading with a lower level. skipped levels (e.g. h2 -> h4), empty headings
+// and pages without exactly one h1 are reported
+func (w *webAnalyzerUtilsImpl) DetectHeadingOutline(ctx context.Context, doc *goquery.Document) response_dtos.HeadingOutline {
+	outline := response_dtos.HeadingOutline{
+		Outline: []*response_dtos.HeadingNode{},
+		Issues:  []response_dtos.AnalysisIssue{},
+	}
+
+	var parents []*response_dtos.HeadingNode // open headings from the top level to the last heading
+	previousLevel, h1Count := 0, 0
+
+	doc.Find("h1, h2, h3, h4, h5, h6").Each(func(i int, s *goquery.Selection) {
+		level := int(goquery.NodeName(s)[1] - '0')
+		node := &response_dtos.HeadingNode{
+			Level: level,
+			Text:  headingText(s),
+		}
+
+		if level == 1 {
+			h1Count++
+		}
+		if node.Text == "" {
+			outline.Issues = append(outline.Issues, newElementIssue("empty_heading", IssueSeverityWarning, fmt.Sprintf("the h%v heading has no text", level), elementPath(s)))
+		}
+		if previousLevel > 0 && level > previousLevel+1 {
+			outline.Issues = append(outline.Issues, newElementIssue("skipped_heading_level", IssueSeverityWarning, fmt.Sprintf("the heading level jumps from h%v to h%v", previousLevel, level), elementPath(s)))
+		}
+		previousLevel = level
+
+		for len(parents) > 0 && parents[len(parents)-1].Level >= level {
+			parents = parents[:len(parents)-1]
+		}
+		if len(parents) == 0 {
+			outline.Outline = append(outline.Outline, node)
+		} else {
+			parent := parents[len(parents)-1]
+			parent.Children = append(parent.Children, node)
+		}
+		parents = append(parents, node)
+	})
+
+	switch {
+	case h1Count == 0:
+		outline.Issues = append(outline.Issues, newIssue("missing_h1", IssueSeverityError, "the page has no h1 heading"))
+	case h1Count > 1:
+		outline.Issues = append(outline.Issues, newIssue("multiple_h1", IssueSeverityWarning, fmt.Sprintf("the page has %v h1 headings", h1Count)))
+	}
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified %v heading outline issues", len(outline.Issues)), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+
+	return outline
+}
+
+// headingText - returns the whitespace normalized text of the heading, falling back to
+// the alt text of the images inside it
+func headingText(s *goquery.Selection) string {
+	text := strings.Join(strings.Fields(s.Text()), " ")
+	if text != "" {
+		return text
+	}
+
+	var alts []string
+	s.Find("img[alt]").Each(func(i int, img *goquery.Selection) {
+		if alt := strings.TrimSpace(img.AttrOr("alt", "")); alt != "" {
+			alts = append(alts, alt)
+		}
+	})
+	return strings.Join(alts, " ")
+}
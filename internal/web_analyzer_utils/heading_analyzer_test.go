@@ -0,0 +1,103 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestDetectHeadingOutline(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+	config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1}
+	utils := NewWebAnalyzerUtils(logger, config)
+
+	tests := []struct {
+		name               string
+		html               string
+		expectedOutline    []*response_dtos.HeadingNode
+		expectedIssueCodes []string
+	}{
+		{
+			name: "Valid Outline",
+			html: `<html><body>
+				<h1>Guide</h1>
+				<h2>Install</h2>
+				<h3>  Linux
+				</h3>
+				<h3>macOS</h3>
+				<h2>Usage</h2>
+				</body></html>`,
+			expectedOutline: []*response_dtos.HeadingNode{
+				{Level: 1, Text: "Guide", Children: []*response_dtos.HeadingNode{
+					{Level: 2, Text: "Install", Children: []*response_dtos.HeadingNode{
+						{Level: 3, Text: "Linux"},
+						{Level: 3, Text: "macOS"},
+					}},
+					{Level: 2, Text: "Usage"},
+				}},
+			},
+			expectedIssueCodes: []string{},
+		},
+		{
+			name: "Skipped Levels and Empty Heading",
+			html: `<html><body>
+				<h1>Guide</h1>
+				<h2>Install</h2>
+				<h4>Details</h4>
+				<h2><img src="logo.png" alt="Logo"></h2>
+				<h3> </h3>
+				</body></html>`,
+			expectedOutline: []*response_dtos.HeadingNode{
+				{Level: 1, Text: "Guide", Children: []*response_dtos.HeadingNode{
+					{Level: 2, Text: "Install", Children: []*response_dtos.HeadingNode{
+						{Level: 4, Text: "Details"},
+					}},
+					{Level: 2, Text: "Logo", Children: []*response_dtos.HeadingNode{
+						{Level: 3, Text: ""},
+					}},
+				}},
+			},
+			expectedIssueCodes: []string{"skipped_heading_level", "empty_heading"},
+		},
+		{
+			name:               "No Headings",
+			html:               `<html><body><p>text</p></body></html>`,
+			expectedOutline:    []*response_dtos.HeadingNode{},
+			expectedIssueCodes: []string{"missing_h1"},
+		},
+		{
+			name: "Multiple H1",
+			html: `<html><body><h2>Intro</h2><h1>First</h1><h1>Second</h1></body></html>`,
+			expectedOutline: []*response_dtos.HeadingNode{
+				{Level: 2, Text: "Intro"},
+				{Level: 1, Text: "First"},
+				{Level: 1, Text: "Second"},
+			},
+			expectedIssueCodes: []string{"multiple_h1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatalf("Failed to parse HTML: %v", err)
+			}
+
+			outline := utils.DetectHeadingOutline(context.Background(), doc)
+
+			if !reflect.DeepEqual(outline.Outline, tt.expectedOutline) {
+				t.Errorf("Outline = %v, want %v", outline.Outline, tt.expectedOutline)
+			}
+			if codes := issueCodes(outline.Issues); !reflect.DeepEqual(codes, tt.expectedIssueCodes) {
+				t.Errorf("Issues = %v, want %v", codes, tt.expectedIssueCodes)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errHostRateLimited - returned by the host limiter when the host asked to be left alone for longer than the limiter waits
+var errHostRateLimited = errors.New("host is rate limited")
+
+// hostSweepInterval - how often the host limiter removes the states of the idle hosts
+const hostSweepInterval = time.Minute
+
+// hostLimiter - limits the requests sent to each host, identified by its host and port, to maxConcurrent
+// requests at a time and to one request every interval. a host can be paused until the time given in its
+// Retry-After header, requests to a paused host wait until it is resumed if that is within maxWait.
+// zero values of maxConcurrent and interval leave the hosts unlimited.
+// the states of the idle hosts are removed at most once every hostSweepInterval so the limiter does not
+// grow with every host ever checked
+type hostLimiter struct {
+	maxConcurrent int
+	interval      time.Duration
+	maxWait       time.Duration
+
+	mu        sync.Mutex
+	hosts     map[string]*hostState
+	lastSweep time.Time
+}
+
+// hostState - slots of the requests in flight to a host, the time the next request can be sent,
+// the time the host asked not to be requested before and the number of requests using the state
+type hostState struct {
+	slots       chan struct{}
+	next        time.Time
+	pausedUntil time.Time
+	users       int
+}
+
+// newHostLimiter - creates a host limiter. requestsPerSecond is converted to the interval between two requests
+func newHostLimiter(maxConcurrent int, requestsPerSecond float64, maxWait time.Duration) *hostLimiter {
+	var interval time.Duration
+	if requestsPerSecond > 0 {
+		interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+
+	return &hostLimiter{
+		maxConcurrent: maxConcurrent,
+		interval:      interval,
+		maxWait:       maxWait,
+		hosts:         make(map[string]*hostState),
+		lastSweep:     time.Now(),
+	}
+}
+
+// state - returns the state of the host, creating it on the first request to the host.
+// the state is kept until it is given back with done
+func (l *hostLimiter) state(host string) *hostState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now := time.Now(); now.Sub(l.lastSweep) >= hostSweepInterval {
+		l.removeIdleHosts(now)
+	}
+
+	state, ok := l.hosts[host]
+	if !ok {
+		state = &hostState{}
+		if l.maxConcurrent > 0 {
+			state.slots = make(chan struct{}, l.maxConcurrent)
+		}
+		l.hosts[host] = state
+	}
+	state.users++
+	return state
+}
+
+// done - gives back a state returned by state
+func (l *hostLimiter) done(state *hostState) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state.users--
+}
+
+// removeIdleHosts - removes the states of the hosts which are not used by any request and can be requested
+// right away, since a new state is the same as theirs. must be called while holding the lock
+func (l *hostLimiter) removeIdleHosts(now time.Time) {
+	for host, state := range l.hosts {
+		if state.users == 0 && len(state.slots) == 0 && !state.next.After(now) && !state.pausedUntil.After(now) {
+			delete(l.hosts, host)
+		}
+	}
+	l.lastSweep = now
+}
+
+// acquire - waits until a request can be sent to the host and returns the function which releases its slot.
+// returns errHostRateLimited without waiting when the host is paused for longer than maxWait
+// and the error of ctx when it is done before the request can be sent
+func (l *hostLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	state := l.state(host)
+
+	if err := l.checkPause(state); err != nil {
+		l.done(state)
+		return nil, err
+	}
+
+	release := func() { l.done(state) }
+	if state.slots != nil {
+		select {
+		case state.slots <- struct{}{}:
+			release = func() {
+				<-state.slots
+				l.done(state)
+			}
+		case <-ctx.Done():
+			l.done(state)
+			return nil, ctx.Err()
+		}
+	}
+
+	// reserve the next free time of the host so the requests waiting for it are sent one interval apart
+	l.mu.Lock()
+	at := time.Now()
+	if state.next.After(at) {
+		at = state.next
+	}
+	if state.pausedUntil.After(at) {
+		at = state.pausedUntil
+	}
+	state.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	if err := SleepContext(ctx, time.Until(at)); err != nil {
+		release()
+		return nil, err
+	}
+
+	// the host may have been paused by another request while this one was waiting
+	if err := l.checkPause(state); err != nil {
+		release()
+		return nil, err
+	}
+	l.mu.Lock()
+	pausedUntil := state.pausedUntil
+	l.mu.Unlock()
+	if err := SleepContext(ctx, time.Until(pausedUntil)); err != nil {
+		release()
+		return nil, err
+	}
+
+	return release, nil
+}
+
+// checkPause - returns errHostRateLimited when the host is paused for longer than maxWait
+func (l *hostLimiter) checkPause(state *hostState) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if time.Until(state.pausedUntil) > l.maxWait {
+		return errHostRateLimited
+	}
+	return nil
+}
+
+// pause - stops the requests to the host until the given time
+func (l *hostLimiter) pause(host string, until time.Time) {
+	state := l.state(host)
+	defer l.done(state)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until.After(state.pausedUntil) {
+		state.pausedUntil = until
+	}
+}
+
+// parseRetryAfter - parses the value of a Retry-After header, which is either a number of seconds or a http date,
+// and returns the time to wait from now. returns false when the value is missing or invalid
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if wait := date.Sub(now); wait > 0 {
+		return wait, true
+	}
+	return 0, true
+}
@@ -0,0 +1,221 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		value      string
+		expected   time.Duration
+		expectedOk bool
+	}{
+		{name: "Seconds", value: "120", expected: 2 * time.Minute, expectedOk: true},
+		{name: "Zero Seconds", value: " 0 ", expected: 0, expectedOk: true},
+		{name: "Http Date", value: "Sat, 01 Mar 2025 12:00:30 GMT", expected: 30 * time.Second, expectedOk: true},
+		{name: "Http Date In The Past", value: "Sat, 01 Mar 2025 11:00:00 GMT", expected: 0, expectedOk: true},
+		{name: "Negative Seconds", value: "-5", expectedOk: false},
+		{name: "Missing", value: "", expectedOk: false},
+		{name: "Invalid", value: "soon", expectedOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, ok := parseRetryAfter(tt.value, now)
+			if ok != tt.expectedOk {
+				t.Fatalf("parseRetryAfter() ok = %v, want %v", ok, tt.expectedOk)
+			}
+			if wait != tt.expected {
+				t.Errorf("parseRetryAfter() = %v, want %v", wait, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHostLimiterRemovesIdleHosts(t *testing.T) {
+	limiter := newHostLimiter(1, 0, time.Second)
+
+	release, err := limiter.acquire(context.Background(), "idle.test")
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	release()
+
+	releaseBusy, err := limiter.acquire(context.Background(), "busy.test")
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	defer releaseBusy()
+
+	limiter.pause("paused.test", time.Now().Add(time.Minute))
+
+	if len(limiter.hosts) != 3 {
+		t.Fatalf("hosts = %v, want 3", len(limiter.hosts))
+	}
+
+	// the next request after the sweep interval removes the idle hosts
+	limiter.mu.Lock()
+	limiter.lastSweep = time.Now().Add(-hostSweepInterval)
+	limiter.mu.Unlock()
+	limiter.done(limiter.state("new.test"))
+
+	hosts := make(map[string]bool)
+	for host := range limiter.hosts {
+		hosts[host] = true
+	}
+	expected := map[string]bool{"busy.test": true, "paused.test": true, "new.test": true}
+	if !reflect.DeepEqual(hosts, expected) {
+		t.Errorf("hosts = %v, want %v", hosts, expected)
+	}
+}
+
+func TestIsLinksAccessibleHostLimits(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+
+	t.Run("Concurrency Per Host", func(t *testing.T) {
+		var inFlight, maxInFlight atomic.Int64
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				seen := maxInFlight.Load()
+				if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+		}))
+		defer srv.Close()
+
+		config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 10, MaxLinkChecksPerHost: 2}
+		utils := NewWebAnalyzerUtils(logger, config)
+		base, _ := url.Parse(srv.URL)
+
+		links := []string{"/1", "/2", "/3", "/4", "/5", "/6", "/7", "/8"}
+		report := utils.IsLinksAccessible(context.Background(), links, base)
+
+		if report.InaccessibleLinks != 0 {
+			t.Errorf("IsLinksAccessible() = %v, want 0", report.InaccessibleLinks)
+		}
+		if maxInFlight.Load() > 2 {
+			t.Errorf("requests in flight = %v, want at most 2", maxInFlight.Load())
+		}
+	})
+
+	t.Run("Concurrency Per Host Across Calls", func(t *testing.T) {
+		var inFlight, maxInFlight atomic.Int64
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				seen := maxInFlight.Load()
+				if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+		}))
+		defer srv.Close()
+
+		config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 10, MaxLinkChecksPerHost: 2}
+		utils := NewWebAnalyzerUtils(logger, config)
+		base, _ := url.Parse(srv.URL)
+
+		// two analyses checking links of the same host at the same time share its limit
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				utils.IsLinksAccessible(context.Background(), []string{"/1", "/2", "/3", "/4", "/5", "/6"}, base)
+			}()
+		}
+		wg.Wait()
+
+		if maxInFlight.Load() > 2 {
+			t.Errorf("requests in flight = %v, want at most 2", maxInFlight.Load())
+		}
+	})
+
+	t.Run("Requests Per Second", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer srv.Close()
+
+		config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 5, LinkCheckRequestsPerSecond: 20}
+		utils := NewWebAnalyzerUtils(logger, config)
+		base, _ := url.Parse(srv.URL)
+
+		start := time.Now()
+		utils.IsLinksAccessible(context.Background(), []string{"/1", "/2", "/3", "/4", "/5"}, base)
+
+		// the first request is sent right away and the other four one interval of 50ms apart
+		if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+			t.Errorf("IsLinksAccessible() took %v, want at least 200ms", elapsed)
+		}
+	})
+
+	t.Run("Retry-After Within Limit", func(t *testing.T) {
+		var requests atomic.Int64
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
+		}))
+		defer srv.Close()
+
+		config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1, MaxRetryAfterSeconds: 2}
+		utils := NewWebAnalyzerUtils(logger, config)
+		base, _ := url.Parse(srv.URL)
+
+		start := time.Now()
+		report := utils.IsLinksAccessible(context.Background(), []string{"/throttled", "/next"}, base)
+
+		if report.Links[0].Status != LinkStatusAccessible || report.Links[1].Status != LinkStatusAccessible {
+			t.Errorf("Statuses = %v and %v, want %v", report.Links[0].Status, report.Links[1].Status, LinkStatusAccessible)
+		}
+		if elapsed := time.Since(start); elapsed < time.Second {
+			t.Errorf("IsLinksAccessible() took %v, want at least the 1s of Retry-After", elapsed)
+		}
+	})
+
+	t.Run("Retry-After Over Limit", func(t *testing.T) {
+		var requests atomic.Int64
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1, MaxRetryAfterSeconds: 1}
+		utils := NewWebAnalyzerUtils(logger, config)
+		base, _ := url.Parse(srv.URL)
+
+		report := utils.IsLinksAccessible(context.Background(), []string{"/a", "/b", "/c"}, base)
+
+		if report.Links[0].Status != LinkStatusInaccessible || report.Links[0].StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("first link = %v %v, want %v %v", report.Links[0].Status, report.Links[0].StatusCode, LinkStatusInaccessible, http.StatusServiceUnavailable)
+		}
+		if report.RateLimitedLinks != 2 {
+			t.Errorf("IsLinksAccessible() rate limited = %v, want 2", report.RateLimitedLinks)
+		}
+		if requests.Load() != 1 {
+			t.Errorf("requests sent = %v, want 1", requests.Load())
+		}
+	})
+}
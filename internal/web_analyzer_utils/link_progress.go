@@ -0,0 +1,20 @@
+package web_analyzer_utils
+
+import "context"
+
+type linkProgressKey struct{}
+
+// LinkProgressFunc - receives the number of checked links and the total number of links to check
+type LinkProgressFunc func(checked int, total int)
+
+// WithLinkProgress - returns a copy of ctx which makes IsLinksAccessible report its progress to fn
+func WithLinkProgress(ctx context.Context, fn LinkProgressFunc) context.Context {
+	return context.WithValue(ctx, linkProgressKey{}, fn)
+}
+
+// reportLinkProgress - calls the progress function attached to ctx, if there is one
+func reportLinkProgress(ctx context.Context, checked int, total int) {
+	if fn, ok := ctx.Value(linkProgressKey{}).(LinkProgressFunc); ok && fn != nil {
+		fn(checked, total)
+	}
+}
@@ -0,0 +1,57 @@
+package web_analyzer_utils
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"net/url"
+	"strings"
+)
+
+// scope policies which decide if a link is internal to the web page
+const (
+	LinkScopeExactHost         = "exact_host"
+	LinkScopeSubdomains        = "subdomains"
+	LinkScopeRegistrableDomain = "registrable_domain"
+)
+
+// DocumentBaseURL - returns the url the relative urls of the web page are resolved against, which is the href of
+// the first <base> element resolved against the page url. the page url is returned when there is no valid <base href>
+func DocumentBaseURL(doc *goquery.Document, pageURL *url.URL) *url.URL {
+	href, exists := doc.Find("base[href]").First().Attr("href")
+	if !exists {
+		return pageURL
+	}
+
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return pageURL
+	}
+	return pageURL.ResolveReference(ref)
+}
+
+// resolveReference - resolves the trimmed reference against the base url
+func resolveReference(base *url.URL, reference string) (*url.URL, error) {
+	ref, err := url.Parse(strings.TrimSpace(reference))
+	if err != nil {
+		return nil, err
+	}
+	return base.ResolveReference(ref), nil
+}
+
+// IsInScope - checks if the link is in the scope of the page url under the given scope policy
+// - exact_host - the link has the same host as the page
+// - subdomains - the link has the same host as the page or a subdomain of it
+// - registrable_domain - the link has the same registrable domain (eTLD+1) as the page
+// unknown and empty policies fall back to exact_host. ports are not compared
+func IsInScope(scope string, pageURL *url.URL, linkURL *url.URL) bool {
+	pageHost := strings.TrimSuffix(strings.ToLower(pageURL.Hostname()), ".")
+	linkHost := strings.TrimSuffix(strings.ToLower(linkURL.Hostname()), ".")
+
+	switch scope {
+	case LinkScopeSubdomains:
+		return linkHost == pageHost || strings.HasSuffix(linkHost, "."+pageHost)
+	case LinkScopeRegistrableDomain:
+		return registrableDomain(linkHost) == registrableDomain(pageHost)
+	default:
+		return linkHost == pageHost
+	}
+}
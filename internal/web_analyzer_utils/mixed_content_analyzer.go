@@ -0,0 +1,129 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/PuerkitoBio/goquery"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// types of mixed content. active content can change the page and is blocked by browsers,
+// passive content is only displayed
+const (
+	MixedContentActive  = "active"
+	MixedContentPassive = "passive"
+)
+
+// mixedContentSources - elements and attributes which load a subresource, with the type of mixed content they cause
+var mixedContentSources = []struct {
+	selector  string
+	attribute string
+	kind      string
+}{
+	{"img[src]", "src", MixedContentPassive},
+	{"img[srcset]", "srcset", MixedContentPassive},
+	{"picture source[srcset]", "srcset", MixedContentPassive},
+	{"audio[src]", "src", MixedContentPassive},
+	{"video[src]", "src", MixedContentPassive},
+	{"video[poster]", "poster", MixedContentPassive},
+	{"audio source[src], video source[src]", "src", MixedContentPassive},
+	{"script[src]", "src", MixedContentActive},
+	{"iframe[src]", "src", MixedContentActive},
+	{"frame[src]", "src", MixedContentActive},
+	{"object[data]", "data", MixedContentActive},
+	{"embed[src]", "src", MixedContentActive},
+	{"form[action]", "action", MixedContentActive},
+}
+
+// cssURLRegex - matches url(...) and @import "..." references in css
+var cssURLRegex = regexp.MustCompile(`(?i)url\(\s*['"]?([^'")\s]+)['"]?\s*\)|@import\s+['"]([^'"]+)['"]`)
+
+// DetectMixedContent - scans the subresources of an https page for references which are loaded over http.
+// images, audio and video are passive mixed content. scripts, stylesheets, frames, plugins, form actions
+// and url() references in inline styles are active mixed content. nothing is reported for http pages
+func (w *webAnalyzerUtilsImpl) DetectMixedContent(ctx context.Context, doc *goquery.Document, pageURL *url.URL) response_dtos.MixedContentAnalysis {
+	analysis := response_dtos.MixedContentAnalysis{
+		Applicable: pageURL.Scheme == "https",
+		Findings:   []response_dtos.MixedContentFinding{},
+	}
+	if !analysis.Applicable {
+		return analysis
+	}
+	baseURL := DocumentBaseURL(doc, pageURL)
+
+	addFinding := func(s *goquery.Selection, attribute string, reference string, kind string) {
+		resourceURL, err := resolveReference(baseURL, reference)
+		if err != nil {
+			return
+		}
+		if resourceURL.Scheme != "http" {
+			return
+		}
+
+		analysis.Findings = append(analysis.Findings, response_dtos.MixedContentFinding{
+			Url:       resourceURL.String(),
+			Element:   goquery.NodeName(s),
+			Attribute: attribute,
+			Type:      kind,
+			Path:      elementPath(s),
+		})
+		if kind == MixedContentActive {
+			analysis.ActiveCount++
+		} else {
+			analysis.PassiveCount++
+		}
+	}
+
+	for _, source := range mixedContentSources {
+		doc.Find(source.selector).Each(func(i int, s *goquery.Selection) {
+			value := s.AttrOr(source.attribute, "")
+			if source.attribute != "srcset" {
+				addFinding(s, source.attribute, value, source.kind)
+				return
+			}
+			for _, candidate := range strings.Split(value, ",") {
+				if fields := strings.Fields(candidate); len(fields) > 0 {
+					addFinding(s, source.attribute, fields[0], source.kind)
+				}
+			}
+		})
+	}
+
+	doc.Find("link[href]").Each(func(i int, s *goquery.Selection) {
+		if hasRel(s, "stylesheet") {
+			addFinding(s, "href", s.AttrOr("href", ""), MixedContentActive)
+		}
+	})
+
+	doc.Find("[style]").Each(func(i int, s *goquery.Selection) {
+		for _, reference := range cssURLs(s.AttrOr("style", "")) {
+			addFinding(s, "style", reference, MixedContentActive)
+		}
+	})
+	doc.Find("style").Each(func(i int, s *goquery.Selection) {
+		for _, reference := range cssURLs(s.Text()) {
+			addFinding(s, "", reference, MixedContentActive)
+		}
+	})
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified %v active and %v passive mixed content references", analysis.ActiveCount, analysis.PassiveCount), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+
+	return analysis
+}
+
+// cssURLs - returns the urls referenced by url() and @import in the css
+func cssURLs(css string) []string {
+	var urls []string
+	for _, match := range cssURLRegex.FindAllStringSubmatch(css, -1) {
+		if match[1] != "" {
+			urls = append(urls, match[1])
+		} else {
+			urls = append(urls, match[2])
+		}
+	}
+	return urls
+}
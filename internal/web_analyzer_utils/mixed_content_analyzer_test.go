@@ -0,0 +1,96 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestDetectMixedContent(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+	config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1}
+	utils := NewWebAnalyzerUtils(logger, config)
+
+	page := `<html><head>
+		<link rel="stylesheet" href="http://cdn.example.com/site.css">
+		<link rel="stylesheet" href="/local.css">
+		<script src="http://cdn.example.com/app.js"></script>
+		<script src="//cdn.example.com/lib.js"></script>
+		<style>@import "http://fonts.example.com/font.css"; body { background: url('https://example.com/bg.png'); }</style>
+		</head><body>
+		<img src="http://images.example.com/a.png" srcset="https://images.example.com/a-1x.png 1x, http://images.example.com/a-2x.png 2x">
+		<video poster="http://media.example.com/poster.jpg"><source src="http://media.example.com/clip.mp4"></video>
+		<iframe src="http://widgets.example.com/embed"></iframe>
+		<form action="http://example.com/login"></form>
+		<div style="background-image: url(http://images.example.com/bg.png)"></div>
+		<a href="http://example.com/page">links are not subresources</a>
+		</body></html>`
+
+	tests := []struct {
+		name               string
+		pageURL            string
+		expectedApplicable bool
+		expectedActive     int
+		expectedPassive    int
+		expectedURLs       []string
+	}{
+		{
+			name:               "HTTPS Page",
+			pageURL:            "https://example.com/",
+			expectedApplicable: true,
+			expectedActive:     6,
+			expectedPassive:    4,
+			expectedURLs: []string{
+				"http://images.example.com/a.png",
+				"http://images.example.com/a-2x.png",
+				"http://media.example.com/poster.jpg",
+				"http://media.example.com/clip.mp4",
+				"http://cdn.example.com/app.js",
+				"http://widgets.example.com/embed",
+				"http://example.com/login",
+				"http://cdn.example.com/site.css",
+				"http://images.example.com/bg.png",
+				"http://fonts.example.com/font.css",
+			},
+		},
+		{
+			name:               "HTTP Page",
+			pageURL:            "http://example.com/",
+			expectedApplicable: false,
+			expectedURLs:       []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+			if err != nil {
+				t.Fatalf("Failed to parse HTML: %v", err)
+			}
+			pageURL, _ := url.Parse(tt.pageURL)
+
+			analysis := utils.DetectMixedContent(context.Background(), doc, pageURL)
+
+			if analysis.Applicable != tt.expectedApplicable {
+				t.Errorf("Applicable = %v, want %v", analysis.Applicable, tt.expectedApplicable)
+			}
+			if analysis.ActiveCount != tt.expectedActive || analysis.PassiveCount != tt.expectedPassive {
+				t.Errorf("ActiveCount = %v, PassiveCount = %v, want %v and %v", analysis.ActiveCount, analysis.PassiveCount, tt.expectedActive, tt.expectedPassive)
+			}
+
+			urls := []string{}
+			for _, finding := range analysis.Findings {
+				urls = append(urls, finding.Url)
+			}
+			if !reflect.DeepEqual(urls, tt.expectedURLs) {
+				t.Errorf("Urls = %v, want %v", urls, tt.expectedURLs)
+			}
+		})
+	}
+}
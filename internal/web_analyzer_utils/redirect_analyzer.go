@@ -0,0 +1,102 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"net/http"
+	"net/url"
+)
+
+// DetectRedirectChainIssues - reports the problems in the redirect chain which led to finalURL
+// - a url visited more than once
+// - more than webAnalyzerConfig.MaxRedirectHops hops, not checked when it is zero
+// - a redirect from https to http
+// - permanent (301, 308) and temporary (302, 303, 307) redirects used in the same chain
+// - a hop served over https with a certificate which failed the verification
+// finalURL is empty when the chain stopped at a redirect loop without reaching a page, then the chain
+// is checked up to the location of its last hop
+func (w *webAnalyzerUtilsImpl) DetectRedirectChainIssues(ctx context.Context, hops []response_dtos.RedirectHop, finalURL string) response_dtos.RedirectAnalysis {
+	analysis := response_dtos.RedirectAnalysis{
+		Hops:     hops,
+		HopCount: len(hops),
+		FinalUrl: finalURL,
+		Issues:   []response_dtos.AnalysisIssue{},
+	}
+	if analysis.Hops == nil {
+		analysis.Hops = []response_dtos.RedirectHop{}
+	}
+
+	endURL := finalURL
+	if endURL == "" && len(hops) > 0 {
+		endURL = redirectLocation(hops[len(hops)-1])
+	}
+
+	visited := make(map[string]bool)
+	hasPermanent, hasTemporary := false, false
+
+	for i, hop := range hops {
+		if visited[hop.Url] {
+			analysis.Issues = append(analysis.Issues, newIssue("redirect_loop", IssueSeverityWarning, fmt.Sprintf("the redirect chain visits %v more than once", hop.Url)))
+		}
+		visited[hop.Url] = true
+
+		switch hop.StatusCode {
+		case http.StatusMovedPermanently, http.StatusPermanentRedirect:
+			hasPermanent = true
+		case http.StatusFound, http.StatusSeeOther, http.StatusTemporaryRedirect:
+			hasTemporary = true
+		}
+
+		nextURL := endURL
+		if i+1 < len(hops) {
+			nextURL = hops[i+1].Url
+		}
+		if isHTTPSDowngrade(hop.Url, nextURL) {
+			analysis.Issues = append(analysis.Issues, newIssue("https_downgrade", IssueSeverityError, fmt.Sprintf("%v redirects from https to %v", hop.Url, nextURL)))
+		}
+		if hop.CertificateError != "" {
+			analysis.Issues = append(analysis.Issues, newIssue("untrusted_redirect_certificate", IssueSeverityError, fmt.Sprintf("the certificate of %v is not valid: %v", hop.Url, hop.CertificateError)))
+		}
+	}
+
+	if len(hops) > 0 && visited[endURL] {
+		analysis.Issues = append(analysis.Issues, newIssue("redirect_loop", IssueSeverityWarning, fmt.Sprintf("the redirect chain visits %v more than once", endURL)))
+	}
+	if maxHops := w.webAnalyzerConfig.MaxRedirectHops; maxHops > 0 && len(hops) > maxHops {
+		analysis.Issues = append(analysis.Issues, newIssue("long_redirect_chain", IssueSeverityWarning, fmt.Sprintf("the url is reached after %v redirects, more than the limit of %v", len(hops), maxHops)))
+	}
+	if hasPermanent && hasTemporary {
+		analysis.Issues = append(analysis.Issues, newIssue("mixed_redirect_types", IssueSeverityWarning, "the redirect chain mixes permanent and temporary redirects"))
+	}
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified %v redirects with %v issues", len(hops), len(analysis.Issues)), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+
+	return analysis
+}
+
+// redirectLocation - returns the location of the hop resolved against its url
+func redirectLocation(hop response_dtos.RedirectHop) string {
+	hopURL, err := url.Parse(hop.Url)
+	if err != nil {
+		return hop.Location
+	}
+	location, err := resolveReference(hopURL, hop.Location)
+	if err != nil {
+		return hop.Location
+	}
+	return location.String()
+}
+
+func isHTTPSDowngrade(from string, to string) bool {
+	fromURL, err := url.Parse(from)
+	if err != nil {
+		return false
+	}
+	toURL, err := url.Parse(to)
+	if err != nil {
+		return false
+	}
+	return fromURL.Scheme == "https" && toURL.Scheme == "http"
+}
@@ -0,0 +1,96 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+)
+
+func TestDetectRedirectChainIssues(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+	config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1, MaxRedirectHops: 2}
+	utils := NewWebAnalyzerUtils(logger, config)
+
+	tests := []struct {
+		name               string
+		hops               []response_dtos.RedirectHop
+		finalURL           string
+		expectedHopCount   int
+		expectedIssueCodes []string
+	}{
+		{
+			name:               "No Redirects",
+			finalURL:           "https://example.com/",
+			expectedIssueCodes: []string{},
+		},
+		{
+			name: "Single Permanent Redirect",
+			hops: []response_dtos.RedirectHop{
+				{Url: "http://example.com/", StatusCode: 301, Location: "https://example.com/"},
+			},
+			finalURL:           "https://example.com/",
+			expectedHopCount:   1,
+			expectedIssueCodes: []string{},
+		},
+		{
+			name: "HTTPS Downgrade and Mixed Redirect Types",
+			hops: []response_dtos.RedirectHop{
+				{Url: "https://example.com/", StatusCode: 301, Location: "http://www.example.com/"},
+				{Url: "http://www.example.com/", StatusCode: 302, Location: "/home"},
+			},
+			finalURL:           "http://www.example.com/home",
+			expectedHopCount:   2,
+			expectedIssueCodes: []string{"https_downgrade", "mixed_redirect_types"},
+		},
+		{
+			name: "Loop and Long Chain",
+			hops: []response_dtos.RedirectHop{
+				{Url: "https://example.com/a", StatusCode: 302, Location: "/login"},
+				{Url: "https://example.com/login", StatusCode: 302, Location: "/a"},
+				{Url: "https://example.com/a", StatusCode: 307, Location: "/b"},
+			},
+			finalURL:           "https://example.com/login",
+			expectedHopCount:   3,
+			expectedIssueCodes: []string{"redirect_loop", "redirect_loop", "long_redirect_chain"},
+		},
+		{
+			name: "Loop Without Final Page",
+			hops: []response_dtos.RedirectHop{
+				{Url: "https://example.com/a", StatusCode: 301, Location: "/b"},
+				{Url: "https://example.com/b", StatusCode: 302, Location: "/a"},
+			},
+			finalURL:           "",
+			expectedHopCount:   2,
+			expectedIssueCodes: []string{"redirect_loop", "mixed_redirect_types"},
+		},
+		{
+			name: "Hop With Untrusted Certificate",
+			hops: []response_dtos.RedirectHop{
+				{Url: "https://example.com/", StatusCode: 301, Location: "https://www.example.com/", CertificateError: "x509: certificate signed by unknown authority"},
+			},
+			finalURL:           "https://www.example.com/",
+			expectedHopCount:   1,
+			expectedIssueCodes: []string{"untrusted_redirect_certificate"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := utils.DetectRedirectChainIssues(context.Background(), tt.hops, tt.finalURL)
+
+			if analysis.HopCount != tt.expectedHopCount {
+				t.Errorf("HopCount = %v, want %v", analysis.HopCount, tt.expectedHopCount)
+			}
+			if analysis.FinalUrl != tt.finalURL {
+				t.Errorf("FinalUrl = %v, want %v", analysis.FinalUrl, tt.finalURL)
+			}
+			if codes := issueCodes(analysis.Issues); !reflect.DeepEqual(codes, tt.expectedIssueCodes) {
+				t.Errorf("Issues = %v, want %v", codes, tt.expectedIssueCodes)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/PuerkitoBio/goquery"
+	"net/url"
+	"strings"
+)
+
+// types of the subresources in the resource inventory
+const (
+	ResourceTypeScript     = "script"
+	ResourceTypeStylesheet = "stylesheet"
+	ResourceTypeImage      = "image"
+	ResourceTypeMedia      = "media"
+	ResourceTypeFont       = "font"
+	ResourceTypeIframe     = "iframe"
+)
+
+// script types which are executed by the browser. other types, such as application/ld+json, hold data
+var executableScriptTypes = map[string]bool{
+	"":                       true,
+	"module":                 true,
+	"text/javascript":        true,
+	"application/javascript": true,
+	"text/ecmascript":        true,
+	"application/ecmascript": true,
+}
+
+// DetectResources - lists the scripts, stylesheets, images, media, font preloads and iframes of the web page.
+// external resources are resolved against the page url and marked as third-party when they are served from
+// another host. the async, defer, integrity and crossorigin attributes are reported as they are
+func (w *webAnalyzerUtilsImpl) DetectResources(ctx context.Context, doc *goquery.Document, pageURL *url.URL) response_dtos.ResourceInventory {
+	inventory := response_dtos.ResourceInventory{
+		Resources: []response_dtos.Resource{},
+		Counts:    make(map[string]int),
+	}
+	baseURL := DocumentBaseURL(doc, pageURL)
+
+	addResource := func(s *goquery.Selection, resourceType string, reference string) {
+		resource := response_dtos.Resource{
+			Type:        resourceType,
+			Async:       hasAttr(s, "async"),
+			Defer:       hasAttr(s, "defer"),
+			Integrity:   strings.TrimSpace(s.AttrOr("integrity", "")),
+			CrossOrigin: crossOrigin(s),
+			Path:        elementPath(s),
+		}
+
+		if reference == "" {
+			resource.Inline = true
+			inventory.Inline++
+		} else {
+			resourceURL, err := resolveReference(baseURL, reference)
+			if err != nil {
+				return
+			}
+			resource.Url = resourceURL.String()
+			resource.ThirdParty = !IsInScope(w.webAnalyzerConfig.LinkScope, pageURL, resourceURL)
+			if resource.ThirdParty {
+				inventory.ThirdParty++
+			} else {
+				inventory.FirstParty++
+			}
+		}
+
+		inventory.Resources = append(inventory.Resources, resource)
+		inventory.Counts[resourceType]++
+	}
+
+	doc.Find("script").Each(func(i int, s *goquery.Selection) {
+		if !executableScriptTypes[strings.ToLower(strings.TrimSpace(s.AttrOr("type", "")))] {
+			return
+		}
+		if src, exists := s.Attr("src"); exists {
+			addResource(s, ResourceTypeScript, src)
+		} else if strings.TrimSpace(s.Text()) != "" {
+			addResource(s, ResourceTypeScript, "")
+		}
+	})
+
+	doc.Find("link[href], style").Each(func(i int, s *goquery.Selection) {
+		switch {
+		case goquery.NodeName(s) == "style":
+			addResource(s, ResourceTypeStylesheet, "")
+		case hasRel(s, "stylesheet"):
+			addResource(s, ResourceTypeStylesheet, s.AttrOr("href", ""))
+		case hasRel(s, "preload") && strings.EqualFold(s.AttrOr("as", ""), "font"):
+			addResource(s, ResourceTypeFont, s.AttrOr("href", ""))
+		}
+	})
+
+	doc.Find("img[src]").Each(func(i int, s *goquery.Selection) {
+		addResource(s, ResourceTypeImage, s.AttrOr("src", ""))
+	})
+
+	doc.Find("audio[src], video[src], audio source[src], video source[src]").Each(func(i int, s *goquery.Selection) {
+		addResource(s, ResourceTypeMedia, s.AttrOr("src", ""))
+	})
+
+	doc.Find("iframe[src]").Each(func(i int, s *goquery.Selection) {
+		addResource(s, ResourceTypeIframe, s.AttrOr("src", ""))
+	})
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified %v resources, %v of them third-party", len(inventory.Resources), inventory.ThirdParty), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+
+	return inventory
+}
+
+// ResourceURLs - returns the distinct urls of the external resources in the inventory
+func ResourceURLs(inventory response_dtos.ResourceInventory) []string {
+	seen := make(map[string]bool)
+	var urls []string
+	for _, resource := range inventory.Resources {
+		if resource.Inline || seen[resource.Url] {
+			continue
+		}
+		seen[resource.Url] = true
+		urls = append(urls, resource.Url)
+	}
+	return urls
+}
+
+// crossOrigin - returns the crossorigin attribute, an empty value means anonymous
+func crossOrigin(s *goquery.Selection) string {
+	value, exists := s.Attr("crossorigin")
+	if !exists {
+		return ""
+	}
+	if value = strings.ToLower(strings.TrimSpace(value)); value == "" {
+		return "anonymous"
+	}
+	return value
+}
+
+func hasAttr(s *goquery.Selection, name string) bool {
+	_, exists := s.Attr(name)
+	return exists
+}
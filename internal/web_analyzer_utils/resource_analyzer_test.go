@@ -0,0 +1,83 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestDetectResources(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+	config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1}
+	utils := NewWebAnalyzerUtils(logger, config)
+
+	page := `<html><head>
+		<script src="/static/app.js" defer></script>
+		<script src="https://cdn.example.net/lib.js" async integrity="sha384-abc" crossorigin></script>
+		<script>window.dataLayer = [];</script>
+		<script type="application/ld+json">{"@type": "Organization"}</script>
+		<link rel="stylesheet" href="css/site.css">
+		<link rel="preload" href="https://fonts.example.net/font.woff2" as="font" crossorigin="use-credentials">
+		<link rel="icon" href="/favicon.ico">
+		<style>body { margin: 0; }</style>
+		</head><body>
+		<img src="/static/logo.png">
+		<img src="/static/logo.png">
+		<video src="https://media.example.net/clip.mp4"><source src="/clip.webm"></video>
+		<iframe src="https://www.youtube.com/embed/1"></iframe>
+		</body></html>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("Failed to parse HTML: %v", err)
+	}
+	pageURL, _ := url.Parse("https://example.com/docs/")
+
+	inventory := utils.DetectResources(context.Background(), doc, pageURL)
+
+	expectedResources := []response_dtos.Resource{
+		{Type: "script", Url: "https://example.com/static/app.js", Defer: true, Path: "html > head > script:nth-of-type(1)"},
+		{Type: "script", Url: "https://cdn.example.net/lib.js", ThirdParty: true, Async: true, Integrity: "sha384-abc", CrossOrigin: "anonymous", Path: "html > head > script:nth-of-type(2)"},
+		{Type: "script", Inline: true, Path: "html > head > script:nth-of-type(3)"},
+		{Type: "stylesheet", Url: "https://example.com/docs/css/site.css", Path: "html > head > link:nth-of-type(1)"},
+		{Type: "font", Url: "https://fonts.example.net/font.woff2", ThirdParty: true, CrossOrigin: "use-credentials", Path: "html > head > link:nth-of-type(2)"},
+		{Type: "stylesheet", Inline: true, Path: "html > head > style"},
+		{Type: "image", Url: "https://example.com/static/logo.png", Path: "html > body > img:nth-of-type(1)"},
+		{Type: "image", Url: "https://example.com/static/logo.png", Path: "html > body > img:nth-of-type(2)"},
+		{Type: "media", Url: "https://media.example.net/clip.mp4", ThirdParty: true, Path: "html > body > video"},
+		{Type: "media", Url: "https://example.com/clip.webm", Path: "html > body > video > source"},
+		{Type: "iframe", Url: "https://www.youtube.com/embed/1", ThirdParty: true, Path: "html > body > iframe"},
+	}
+	if !reflect.DeepEqual(inventory.Resources, expectedResources) {
+		t.Errorf("Resources = %+v, want %+v", inventory.Resources, expectedResources)
+	}
+
+	expectedCounts := map[string]int{"script": 3, "stylesheet": 2, "font": 1, "image": 2, "media": 2, "iframe": 1}
+	if !reflect.DeepEqual(inventory.Counts, expectedCounts) {
+		t.Errorf("Counts = %v, want %v", inventory.Counts, expectedCounts)
+	}
+	if inventory.FirstParty != 5 || inventory.ThirdParty != 4 || inventory.Inline != 2 {
+		t.Errorf("FirstParty = %v, ThirdParty = %v, Inline = %v", inventory.FirstParty, inventory.ThirdParty, inventory.Inline)
+	}
+
+	expectedURLs := []string{
+		"https://example.com/static/app.js",
+		"https://cdn.example.net/lib.js",
+		"https://example.com/docs/css/site.css",
+		"https://fonts.example.net/font.woff2",
+		"https://example.com/static/logo.png",
+		"https://media.example.net/clip.mp4",
+		"https://example.com/clip.webm",
+		"https://www.youtube.com/embed/1",
+	}
+	if urls := ResourceURLs(inventory); !reflect.DeepEqual(urls, expectedURLs) {
+		t.Errorf("ResourceURLs() = %v, want %v", urls, expectedURLs)
+	}
+}
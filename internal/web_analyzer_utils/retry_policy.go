@@ -0,0 +1,137 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// values of the retry policy used for the settings which are not configured
+const (
+	defaultRetryInitialBackoff = 200 * time.Millisecond
+	defaultRetryMaxBackoff     = 5 * time.Second
+	defaultRetryMultiplier     = 2
+)
+
+var defaultRetryableErrorClasses = []string{LinkErrorTimeout, LinkErrorConnectionReset}
+
+var defaultRetryableStatusCodes = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+// RetryPolicy - decides if a failed request is sent again and how long to wait before the next attempt.
+// the wait grows exponentially from the initial backoff by the multiplier up to the max backoff
+// and is spread by the jitter, a fraction of the wait added or taken away at random
+type RetryPolicy struct {
+	maxAttempts          int
+	initialBackoff       time.Duration
+	maxBackoff           time.Duration
+	multiplier           float64
+	jitter               float64
+	retryableErrors      map[string]bool
+	retryableStatusCodes map[int]bool
+}
+
+// NewRetryPolicy - creates the retry policy from the Retry* settings of webAnalyzerConfig.
+// a request is attempted once when webAnalyzerConfig is nil or RetryMaxAttempts is not set, and the
+// error classes and status codes fall back to timeout, connection_reset, 502, 503 and 504 when they are not set
+func NewRetryPolicy(webAnalyzerConfig *configurations.WebAnalyzerConfigurations) *RetryPolicy {
+	if webAnalyzerConfig == nil {
+		webAnalyzerConfig = &configurations.WebAnalyzerConfigurations{}
+	}
+
+	policy := &RetryPolicy{
+		maxAttempts:          max(webAnalyzerConfig.RetryMaxAttempts, 1),
+		initialBackoff:       time.Duration(webAnalyzerConfig.RetryInitialBackoffMs) * time.Millisecond,
+		maxBackoff:           time.Duration(webAnalyzerConfig.RetryMaxBackoffMs) * time.Millisecond,
+		multiplier:           webAnalyzerConfig.RetryBackoffMultiplier,
+		jitter:               min(max(webAnalyzerConfig.RetryJitter, 0), 1),
+		retryableErrors:      make(map[string]bool),
+		retryableStatusCodes: make(map[int]bool),
+	}
+	if policy.initialBackoff <= 0 {
+		policy.initialBackoff = defaultRetryInitialBackoff
+	}
+	if policy.maxBackoff <= 0 {
+		policy.maxBackoff = defaultRetryMaxBackoff
+	}
+	if policy.multiplier < 1 {
+		policy.multiplier = defaultRetryMultiplier
+	}
+
+	errorClasses := webAnalyzerConfig.RetryableErrorClasses
+	if len(errorClasses) == 0 {
+		errorClasses = defaultRetryableErrorClasses
+	}
+	for _, errorClass := range errorClasses {
+		policy.retryableErrors[errorClass] = true
+	}
+
+	statusCodes := webAnalyzerConfig.RetryableStatusCodes
+	if len(statusCodes) == 0 {
+		statusCodes = defaultRetryableStatusCodes
+	}
+	for _, statusCode := range statusCodes {
+		policy.retryableStatusCodes[statusCode] = true
+	}
+
+	return policy
+}
+
+// MaxAttempts - returns the number of times a request is sent at most
+func (p *RetryPolicy) MaxAttempts() int {
+	return p.maxAttempts
+}
+
+// ShouldRetry - checks if a request which failed on the given attempt, counted from 1, is sent again.
+// a transport error is retried when its class (see the LinkError* classes) is retryable,
+// otherwise the status code of the response is checked
+func (p *RetryPolicy) ShouldRetry(attempt int, statusCode int, err error) bool {
+	if attempt >= p.maxAttempts {
+		return false
+	}
+	if err != nil {
+		return p.retryableErrors[classifyLinkError(err)]
+	}
+	return p.retryableStatusCodes[statusCode]
+}
+
+// Backoff - returns the wait before the attempt which follows the given attempt
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := float64(p.initialBackoff)
+	for i := 1; i < attempt && backoff < float64(p.maxBackoff); i++ {
+		backoff *= p.multiplier
+	}
+	backoff = min(backoff, float64(p.maxBackoff))
+
+	if p.jitter > 0 {
+		backoff += backoff * p.jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(backoff)
+}
+
+// Delay - returns the wait before the attempt which follows the given attempt, which is the Retry-After
+// of the response, up to the max backoff, when the header has one and the backoff otherwise
+func (p *RetryPolicy) Delay(attempt int, header http.Header) time.Duration {
+	if wait, ok := parseRetryAfter(header.Get("Retry-After"), time.Now()); ok {
+		return min(wait, p.maxBackoff)
+	}
+	return p.Backoff(attempt)
+}
+
+// SleepContext - waits for the duration or until ctx is done, whichever comes first
+func SleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
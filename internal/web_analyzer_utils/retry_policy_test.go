@@ -0,0 +1,179 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+)
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	policy := NewRetryPolicy(&configurations.WebAnalyzerConfigurations{RetryMaxAttempts: 3})
+	custom := NewRetryPolicy(&configurations.WebAnalyzerConfigurations{
+		RetryMaxAttempts:      3,
+		RetryableErrorClasses: []string{LinkErrorConnectionRefused},
+		RetryableStatusCodes:  []int{http.StatusTooManyRequests},
+	})
+
+	tests := []struct {
+		name       string
+		policy     *RetryPolicy
+		attempt    int
+		statusCode int
+		err        error
+		expected   bool
+	}{
+		{name: "Timeout", policy: policy, attempt: 1, err: context.DeadlineExceeded, expected: true},
+		{name: "Connection Reset", policy: policy, attempt: 2, err: &url.Error{Op: "Head", Err: syscall.ECONNRESET}, expected: true},
+		{name: "Last Attempt", policy: policy, attempt: 3, err: context.DeadlineExceeded, expected: false},
+		{name: "DNS Error", policy: policy, attempt: 1, err: &net.DNSError{Err: "no such host", IsNotFound: true}, expected: false},
+		{name: "Bad Gateway", policy: policy, attempt: 1, statusCode: http.StatusBadGateway, expected: true},
+		{name: "Not Found", policy: policy, attempt: 1, statusCode: http.StatusNotFound, expected: false},
+		{name: "Configured Error Class", policy: custom, attempt: 1, err: syscall.ECONNREFUSED, expected: true},
+		{name: "Default Error Class Not Configured", policy: custom, attempt: 1, err: context.DeadlineExceeded, expected: false},
+		{name: "Configured Status Code", policy: custom, attempt: 1, statusCode: http.StatusTooManyRequests, expected: true},
+		{name: "Single Attempt By Default", policy: NewRetryPolicy(nil), attempt: 1, err: context.DeadlineExceeded, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.policy.ShouldRetry(tt.attempt, tt.statusCode, tt.err); result != tt.expected {
+				t.Errorf("ShouldRetry() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := NewRetryPolicy(&configurations.WebAnalyzerConfigurations{
+		RetryMaxAttempts:       5,
+		RetryInitialBackoffMs:  100,
+		RetryMaxBackoffMs:      300,
+		RetryBackoffMultiplier: 2,
+	})
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	for i, want := range expected {
+		if backoff := policy.Backoff(i + 1); backoff != want {
+			t.Errorf("Backoff(%v) = %v, want %v", i+1, backoff, want)
+		}
+	}
+
+	jittered := NewRetryPolicy(&configurations.WebAnalyzerConfigurations{RetryInitialBackoffMs: 100, RetryJitter: 0.5})
+	for i := 0; i < 20; i++ {
+		if backoff := jittered.Backoff(1); backoff < 50*time.Millisecond || backoff > 150*time.Millisecond {
+			t.Errorf("Backoff(1) = %v, want between 50ms and 150ms", backoff)
+		}
+	}
+
+	header := http.Header{}
+	header.Set("Retry-After", "1")
+	if delay := policy.Delay(1, header); delay != 300*time.Millisecond {
+		t.Errorf("Delay() = %v, want the max backoff of 300ms", delay)
+	}
+	if delay := policy.Delay(2, http.Header{}); delay != 200*time.Millisecond {
+		t.Errorf("Delay() = %v, want the backoff of 200ms", delay)
+	}
+}
+
+func TestIsLinksAccessibleRetries(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		count := requests[r.URL.Path]
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/flaky":
+			if count < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+		case "/head-rejected":
+			if r.Method == http.MethodHead {
+				if count < 3 {
+					w.WriteHeader(http.StatusBadGateway)
+				} else {
+					w.WriteHeader(http.StatusMethodNotAllowed)
+				}
+			}
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/reset":
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+		}
+	}))
+	defer srv.Close()
+
+	base, _ := url.Parse(srv.URL)
+
+	tests := []struct {
+		name               string
+		maxAttempts        int
+		link               string
+		expectedStatus     string
+		expectedErrorClass string
+		expectedAttempts   int
+	}{
+		{name: "Recovered After Retries", maxAttempts: 3, link: "/flaky", expectedStatus: LinkStatusAccessible, expectedAttempts: 3},
+		{name: "Attempts Exhausted", maxAttempts: 2, link: "/flaky", expectedStatus: LinkStatusInaccessible, expectedErrorClass: LinkErrorServerError, expectedAttempts: 2},
+		{name: "Attempts Of Head And Get Fallback", maxAttempts: 3, link: "/head-rejected", expectedStatus: LinkStatusAccessible, expectedAttempts: 4},
+		{name: "Status Not Retryable", maxAttempts: 3, link: "/missing", expectedStatus: LinkStatusInaccessible, expectedErrorClass: LinkErrorClientError, expectedAttempts: 1},
+		{name: "Connection Reset", maxAttempts: 2, link: "/reset", expectedStatus: LinkStatusInaccessible, expectedErrorClass: LinkErrorConnectionReset, expectedAttempts: 2},
+		{name: "No Retries By Default", link: "/flaky", expectedStatus: LinkStatusInaccessible, expectedErrorClass: LinkErrorServerError, expectedAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			clear(requests)
+			mu.Unlock()
+
+			config := &configurations.WebAnalyzerConfigurations{
+				MaxLinkAccessCheckerWorkerCount: 1,
+				RetryMaxAttempts:                tt.maxAttempts,
+				RetryInitialBackoffMs:           1,
+			}
+			utils := NewWebAnalyzerUtils(logger, config)
+
+			result := utils.IsLinksAccessible(context.Background(), []string{tt.link}, base).Links[0]
+
+			if result.Status != tt.expectedStatus {
+				t.Errorf("Status = %v, want %v", result.Status, tt.expectedStatus)
+			}
+			if result.ErrorClass != tt.expectedErrorClass {
+				t.Errorf("ErrorClass = %v, want %v", result.ErrorClass, tt.expectedErrorClass)
+			}
+			if result.Attempts != tt.expectedAttempts {
+				t.Errorf("Attempts = %v, want %v", result.Attempts, tt.expectedAttempts)
+			}
+		})
+	}
+}
+
+func TestSleepContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := SleepContext(ctx, time.Minute); !errors.Is(err, context.Canceled) {
+		t.Errorf("SleepContext() = %v, want %v", err, context.Canceled)
+	}
+	if err := SleepContext(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("SleepContext() = %v, want nil", err)
+	}
+}
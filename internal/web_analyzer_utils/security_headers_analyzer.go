@@ -0,0 +1,319 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// verdicts given to a security header
+const (
+	SecurityVerdictGood    = "good"
+	SecurityVerdictWeak    = "weak"
+	SecurityVerdictMissing = "missing"
+	SecurityVerdictInvalid = "invalid"
+)
+
+// minHSTSMaxAge - max-age below 180 days is considered too short for HSTS
+const minHSTSMaxAge = 180 * 24 * 60 * 60
+
+// securityHeaderWeights - points given to each header when it is good. weak headers get half of the points
+var securityHeaderWeights = map[string]int{
+	"Strict-Transport-Security":    20,
+	"Content-Security-Policy":      25,
+	"X-Frame-Options":              15,
+	"X-Content-Type-Options":       10,
+	"Referrer-Policy":              10,
+	"Permissions-Policy":           10,
+	"Cross-Origin-Opener-Policy":   5,
+	"Cross-Origin-Embedder-Policy": 5,
+}
+
+// securityGrades - minimum score of each grade, from the best grade to the worst
+var securityGrades = []struct {
+	grade    string
+	minScore int
+}{
+	{"A", 90},
+	{"B", 75},
+	{"C", 60},
+	{"D", 40},
+	{"F", 0},
+}
+
+var referrerPolicyVerdicts = map[string]string{
+	"no-referrer":                     SecurityVerdictGood,
+	"same-origin":                     SecurityVerdictGood,
+	"strict-origin":                   SecurityVerdictGood,
+	"strict-origin-when-cross-origin": SecurityVerdictGood,
+	"no-referrer-when-downgrade":      SecurityVerdictWeak,
+	"origin":                          SecurityVerdictWeak,
+	"origin-when-cross-origin":        SecurityVerdictWeak,
+	"unsafe-url":                      SecurityVerdictWeak,
+}
+
+// DetectSecurityHeaders - audits the security headers of the response of the page and gives a verdict to each of
+// - Strict-Transport-Security
+// - Content-Security-Policy, parsed into its directives
+// - X-Frame-Options, or the frame-ancestors directive of the content security policy
+// - X-Content-Type-Options
+// - Referrer-Policy
+// - Permissions-Policy
+// - Cross-Origin-Opener-Policy and Cross-Origin-Embedder-Policy
+// the overall grade is computed from the weighted verdicts
+func (w *webAnalyzerUtilsImpl) DetectSecurityHeaders(ctx context.Context, header http.Header, pageURL *url.URL) response_dtos.SecurityHeadersAnalysis {
+	analysis := response_dtos.SecurityHeadersAnalysis{
+		Headers: []response_dtos.SecurityHeaderVerdict{},
+		Issues:  []response_dtos.AnalysisIssue{},
+	}
+
+	analysis.CSP = parseCSP(header.Get("Content-Security-Policy"))
+	if analysis.CSP.UnsafeInline {
+		analysis.Issues = append(analysis.Issues, newIssue("csp_unsafe_inline", IssueSeverityWarning, "the content security policy allows inline scripts with 'unsafe-inline'"))
+	}
+	if analysis.CSP.UnsafeEval {
+		analysis.Issues = append(analysis.Issues, newIssue("csp_unsafe_eval", IssueSeverityWarning, "the content security policy allows eval with 'unsafe-eval'"))
+	}
+
+	analysis.Headers = append(analysis.Headers,
+		checkHSTS(header, pageURL),
+		checkCSP(header, analysis.CSP),
+		checkFraming(header, analysis.CSP),
+		checkContentTypeOptions(header),
+		checkReferrerPolicy(header),
+		checkPermissionsPolicy(header),
+		checkCrossOriginPolicy(header, "Cross-Origin-Opener-Policy", []string{"same-origin", "same-origin-allow-popups", "noopener-allow-popups"}),
+		checkCrossOriginPolicy(header, "Cross-Origin-Embedder-Policy", []string{"require-corp", "credentialless"}),
+	)
+
+	for _, verdict := range analysis.Headers {
+		switch verdict.Verdict {
+		case SecurityVerdictGood:
+			analysis.Score += securityHeaderWeights[verdict.Header]
+		case SecurityVerdictWeak:
+			analysis.Score += securityHeaderWeights[verdict.Header] / 2
+		}
+	}
+	for _, grade := range securityGrades {
+		if analysis.Score >= grade.minScore {
+			analysis.Grade = grade.grade
+			break
+		}
+	}
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("graded the security headers as %v with a score of %v", analysis.Grade, analysis.Score), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+
+	return analysis
+}
+
+// parseCSP - splits the content security policy into its directives. only the first occurrence
+// of a directive is used, as browsers do
+func parseCSP(policy string) response_dtos.ContentSecurityPolicy {
+	csp := response_dtos.ContentSecurityPolicy{
+		Directives: make(map[string][]string),
+	}
+
+	for _, directive := range strings.Split(policy, ";") {
+		fields := strings.Fields(directive)
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.ToLower(fields[0])
+		if _, exists := csp.Directives[name]; exists {
+			continue
+		}
+		csp.Directives[name] = append([]string{}, fields[1:]...)
+	}
+
+	// scripts are governed by script-src, falling back to default-src
+	scriptSources, exists := csp.Directives["script-src"]
+	if !exists {
+		scriptSources = csp.Directives["default-src"]
+	}
+	for _, source := range scriptSources {
+		switch strings.ToLower(source) {
+		case "'unsafe-inline'":
+			csp.UnsafeInline = true
+		case "'unsafe-eval'":
+			csp.UnsafeEval = true
+		}
+	}
+
+	return csp
+}
+
+func checkHSTS(header http.Header, pageURL *url.URL) response_dtos.SecurityHeaderVerdict {
+	verdict := newHeaderVerdict(header, "Strict-Transport-Security")
+	if verdict.Verdict == SecurityVerdictMissing {
+		return verdict
+	}
+	if pageURL.Scheme != "https" {
+		verdict.Verdict, verdict.Message = SecurityVerdictWeak, "browsers ignore the header when the page is not served over https"
+		return verdict
+	}
+
+	maxAge := -1
+	includeSubDomains := false
+	for _, directive := range strings.Split(verdict.Value, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "max-age":
+			if seconds, err := strconv.Atoi(strings.Trim(strings.TrimSpace(value), `"`)); err == nil {
+				maxAge = seconds
+			}
+		case "includesubdomains":
+			includeSubDomains = true
+		}
+	}
+
+	switch {
+	case maxAge < 0:
+		verdict.Verdict, verdict.Message = SecurityVerdictInvalid, "the header has no valid max-age directive"
+	case maxAge < minHSTSMaxAge:
+		verdict.Verdict, verdict.Message = SecurityVerdictWeak, fmt.Sprintf("max-age of %v seconds is shorter than 180 days", maxAge)
+	case !includeSubDomains:
+		verdict.Verdict, verdict.Message = SecurityVerdictGood, "https is enforced, but not for the subdomains"
+	default:
+		verdict.Verdict, verdict.Message = SecurityVerdictGood, "https is enforced for the domain and its subdomains"
+	}
+	return verdict
+}
+
+func checkCSP(header http.Header, csp response_dtos.ContentSecurityPolicy) response_dtos.SecurityHeaderVerdict {
+	verdict := newHeaderVerdict(header, "Content-Security-Policy")
+	if verdict.Verdict == SecurityVerdictMissing {
+		if header.Get("Content-Security-Policy-Report-Only") != "" {
+			verdict.Message = "the policy is only reported with Content-Security-Policy-Report-Only and not enforced"
+		}
+		return verdict
+	}
+
+	_, hasScriptSrc := csp.Directives["script-src"]
+	_, hasDefaultSrc := csp.Directives["default-src"]
+
+	switch {
+	case len(csp.Directives) == 0:
+		verdict.Verdict, verdict.Message = SecurityVerdictInvalid, "the policy has no directives"
+	case !hasScriptSrc && !hasDefaultSrc:
+		verdict.Verdict, verdict.Message = SecurityVerdictWeak, "the policy does not restrict scripts with script-src or default-src"
+	case csp.UnsafeInline || csp.UnsafeEval:
+		verdict.Verdict, verdict.Message = SecurityVerdictWeak, "the policy allows 'unsafe-inline' or 'unsafe-eval' scripts"
+	default:
+		verdict.Verdict, verdict.Message = SecurityVerdictGood, "scripts are restricted by the policy"
+	}
+	return verdict
+}
+
+// checkFraming - the frame-ancestors directive of the content security policy takes precedence over X-Frame-Options
+func checkFraming(header http.Header, csp response_dtos.ContentSecurityPolicy) response_dtos.SecurityHeaderVerdict {
+	verdict := newHeaderVerdict(header, "X-Frame-Options")
+
+	if ancestors, exists := csp.Directives["frame-ancestors"]; exists {
+		verdict.Verdict, verdict.Message = SecurityVerdictGood, fmt.Sprintf("framing is restricted by frame-ancestors %v", strings.Join(ancestors, " "))
+		if len(ancestors) == 1 && ancestors[0] == "*" {
+			verdict.Verdict, verdict.Message = SecurityVerdictWeak, "frame-ancestors allows any site to frame the page"
+		}
+		return verdict
+	}
+	if verdict.Verdict == SecurityVerdictMissing {
+		verdict.Message = "the page can be framed by any site"
+		return verdict
+	}
+
+	switch value := strings.ToUpper(verdict.Value); {
+	case value == "DENY" || value == "SAMEORIGIN":
+		verdict.Verdict, verdict.Message = SecurityVerdictGood, "framing is restricted"
+	case strings.HasPrefix(value, "ALLOW-FROM"):
+		verdict.Verdict, verdict.Message = SecurityVerdictWeak, "ALLOW-FROM is not supported by modern browsers, use frame-ancestors"
+	default:
+		verdict.Verdict, verdict.Message = SecurityVerdictInvalid, "the value should be DENY or SAMEORIGIN"
+	}
+	return verdict
+}
+
+func checkContentTypeOptions(header http.Header) response_dtos.SecurityHeaderVerdict {
+	verdict := newHeaderVerdict(header, "X-Content-Type-Options")
+	if verdict.Verdict == SecurityVerdictMissing {
+		return verdict
+	}
+
+	if strings.EqualFold(verdict.Value, "nosniff") {
+		verdict.Verdict, verdict.Message = SecurityVerdictGood, "mime type sniffing is disabled"
+	} else {
+		verdict.Verdict, verdict.Message = SecurityVerdictInvalid, "the only valid value is nosniff"
+	}
+	return verdict
+}
+
+// checkReferrerPolicy - the policy can list fallbacks, the last value known by the browser is used
+func checkReferrerPolicy(header http.Header) response_dtos.SecurityHeaderVerdict {
+	verdict := newHeaderVerdict(header, "Referrer-Policy")
+	if verdict.Verdict == SecurityVerdictMissing {
+		return verdict
+	}
+
+	verdict.Verdict, verdict.Message = SecurityVerdictInvalid, "the header has no valid policy"
+	for _, policy := range strings.Split(verdict.Value, ",") {
+		policy = strings.ToLower(strings.TrimSpace(policy))
+		if policyVerdict, known := referrerPolicyVerdicts[policy]; known {
+			verdict.Verdict, verdict.Message = policyVerdict, fmt.Sprintf("the referrer policy is %v", policy)
+		}
+	}
+	return verdict
+}
+
+func checkPermissionsPolicy(header http.Header) response_dtos.SecurityHeaderVerdict {
+	verdict := newHeaderVerdict(header, "Permissions-Policy")
+	if verdict.Verdict == SecurityVerdictMissing {
+		return verdict
+	}
+
+	for _, feature := range strings.Split(verdict.Value, ",") {
+		if name, _, found := strings.Cut(feature, "="); !found || strings.TrimSpace(name) == "" {
+			verdict.Verdict, verdict.Message = SecurityVerdictInvalid, fmt.Sprintf("%q is not a valid feature policy", strings.TrimSpace(feature))
+			return verdict
+		}
+	}
+	verdict.Verdict, verdict.Message = SecurityVerdictGood, "browser features are restricted"
+	return verdict
+}
+
+// checkCrossOriginPolicy - verdict of COOP or COEP. unsafe-none is the default of both and is considered weak
+func checkCrossOriginPolicy(header http.Header, name string, goodValues []string) response_dtos.SecurityHeaderVerdict {
+	verdict := newHeaderVerdict(header, name)
+	if verdict.Verdict == SecurityVerdictMissing {
+		return verdict
+	}
+
+	value, _, _ := strings.Cut(strings.ToLower(verdict.Value), ";") // drop parameters such as report-to
+	value = strings.TrimSpace(value)
+	for _, goodValue := range goodValues {
+		if value == goodValue {
+			verdict.Verdict, verdict.Message = SecurityVerdictGood, fmt.Sprintf("the policy is %v", value)
+			return verdict
+		}
+	}
+	if value == "unsafe-none" {
+		verdict.Verdict, verdict.Message = SecurityVerdictWeak, "unsafe-none does not isolate the page"
+	} else {
+		verdict.Verdict, verdict.Message = SecurityVerdictInvalid, fmt.Sprintf("%q is not a valid policy", value)
+	}
+	return verdict
+}
+
+// newHeaderVerdict - returns a verdict with the value of the header, which is missing when the header is not set
+func newHeaderVerdict(header http.Header, name string) response_dtos.SecurityHeaderVerdict {
+	verdict := response_dtos.SecurityHeaderVerdict{
+		Header: name,
+		Value:  strings.TrimSpace(header.Get(name)),
+	}
+	if verdict.Value == "" {
+		verdict.Verdict, verdict.Message = SecurityVerdictMissing, "the header is not set"
+	}
+	return verdict
+}
@@ -0,0 +1,150 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+)
+
+func TestDetectSecurityHeaders(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+	config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1}
+	utils := NewWebAnalyzerUtils(logger, config)
+
+	httpsURL, _ := url.Parse("https://example.com/")
+	httpURL, _ := url.Parse("http://example.com/")
+
+	tests := []struct {
+		name               string
+		headers            map[string]string
+		pageURL            *url.URL
+		expectedVerdicts   map[string]string
+		expectedGrade      string
+		expectedScore      int
+		expectedIssueCodes []string
+	}{
+		{
+			name: "Hardened Headers",
+			headers: map[string]string{
+				"Strict-Transport-Security":    "max-age=31536000; includeSubDomains",
+				"Content-Security-Policy":      "default-src 'self'; script-src 'self' https://cdn.example.com; frame-ancestors 'none'",
+				"X-Content-Type-Options":       "nosniff",
+				"Referrer-Policy":              "no-referrer, strict-origin-when-cross-origin",
+				"Permissions-Policy":           "geolocation=(), camera=(self)",
+				"Cross-Origin-Opener-Policy":   "same-origin",
+				"Cross-Origin-Embedder-Policy": "require-corp; report-to=\"coep\"",
+			},
+			pageURL: httpsURL,
+			expectedVerdicts: map[string]string{
+				"Strict-Transport-Security":    SecurityVerdictGood,
+				"Content-Security-Policy":      SecurityVerdictGood,
+				"X-Frame-Options":              SecurityVerdictGood,
+				"X-Content-Type-Options":       SecurityVerdictGood,
+				"Referrer-Policy":              SecurityVerdictGood,
+				"Permissions-Policy":           SecurityVerdictGood,
+				"Cross-Origin-Opener-Policy":   SecurityVerdictGood,
+				"Cross-Origin-Embedder-Policy": SecurityVerdictGood,
+			},
+			expectedGrade:      "A",
+			expectedScore:      100,
+			expectedIssueCodes: []string{},
+		},
+		{
+			name: "Weak and Invalid Headers",
+			headers: map[string]string{
+				"Strict-Transport-Security":  "max-age=3600",
+				"Content-Security-Policy":    "default-src 'self'; script-src 'self' 'unsafe-inline' 'unsafe-eval'",
+				"X-Frame-Options":            "ALLOW-FROM https://partner.example.com",
+				"X-Content-Type-Options":     "sniff",
+				"Referrer-Policy":            "unsafe-url",
+				"Permissions-Policy":         "geolocation",
+				"Cross-Origin-Opener-Policy": "unsafe-none",
+			},
+			pageURL: httpsURL,
+			expectedVerdicts: map[string]string{
+				"Strict-Transport-Security":    SecurityVerdictWeak,
+				"Content-Security-Policy":      SecurityVerdictWeak,
+				"X-Frame-Options":              SecurityVerdictWeak,
+				"X-Content-Type-Options":       SecurityVerdictInvalid,
+				"Referrer-Policy":              SecurityVerdictWeak,
+				"Permissions-Policy":           SecurityVerdictInvalid,
+				"Cross-Origin-Opener-Policy":   SecurityVerdictWeak,
+				"Cross-Origin-Embedder-Policy": SecurityVerdictMissing,
+			},
+			expectedGrade:      "F",
+			expectedScore:      36,
+			expectedIssueCodes: []string{"csp_unsafe_inline", "csp_unsafe_eval"},
+		},
+		{
+			name: "No Headers Over HTTP",
+			headers: map[string]string{
+				"Strict-Transport-Security": "max-age=31536000",
+				"X-Frame-Options":           "SAMEORIGIN",
+			},
+			pageURL: httpURL,
+			expectedVerdicts: map[string]string{
+				"Strict-Transport-Security":    SecurityVerdictWeak,
+				"Content-Security-Policy":      SecurityVerdictMissing,
+				"X-Frame-Options":              SecurityVerdictGood,
+				"X-Content-Type-Options":       SecurityVerdictMissing,
+				"Referrer-Policy":              SecurityVerdictMissing,
+				"Permissions-Policy":           SecurityVerdictMissing,
+				"Cross-Origin-Opener-Policy":   SecurityVerdictMissing,
+				"Cross-Origin-Embedder-Policy": SecurityVerdictMissing,
+			},
+			expectedGrade:      "F",
+			expectedScore:      25,
+			expectedIssueCodes: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for name, value := range tt.headers {
+				header.Set(name, value)
+			}
+
+			analysis := utils.DetectSecurityHeaders(context.Background(), header, tt.pageURL)
+
+			verdicts := make(map[string]string)
+			for _, verdict := range analysis.Headers {
+				verdicts[verdict.Header] = verdict.Verdict
+			}
+			if !reflect.DeepEqual(verdicts, tt.expectedVerdicts) {
+				t.Errorf("Verdicts = %v, want %v", verdicts, tt.expectedVerdicts)
+			}
+			if analysis.Grade != tt.expectedGrade {
+				t.Errorf("Grade = %v, want %v", analysis.Grade, tt.expectedGrade)
+			}
+			if analysis.Score != tt.expectedScore {
+				t.Errorf("Score = %v, want %v", analysis.Score, tt.expectedScore)
+			}
+			if codes := issueCodes(analysis.Issues); !reflect.DeepEqual(codes, tt.expectedIssueCodes) {
+				t.Errorf("Issues = %v, want %v", codes, tt.expectedIssueCodes)
+			}
+		})
+	}
+}
+
+func TestParseCSP(t *testing.T) {
+	csp := parseCSP("Default-Src 'self'; img-src * data:;; script-src 'self'; script-src 'unsafe-inline'; upgrade-insecure-requests")
+
+	expected := map[string][]string{
+		"default-src":               {"'self'"},
+		"img-src":                   {"*", "data:"},
+		"script-src":                {"'self'"},
+		"upgrade-insecure-requests": {},
+	}
+	if !reflect.DeepEqual(csp.Directives, expected) {
+		t.Errorf("Directives = %v, want %v", csp.Directives, expected)
+	}
+	if csp.UnsafeInline || csp.UnsafeEval {
+		t.Errorf("UnsafeInline = %v, UnsafeEval = %v, want false", csp.UnsafeInline, csp.UnsafeEval)
+	}
+}
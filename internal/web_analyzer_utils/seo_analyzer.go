@@ -0,0 +1,218 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/PuerkitoBio/goquery"
+	"mime"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// lengths after which search engines usually truncate the title and the description in results
+const (
+	maxSEOTitleLength       = 60
+	maxSEODescriptionLength = 160
+)
+
+// DetectSEOMetadata - extracts the title, meta description, canonical url, robots directives, hreflang alternates,
+// viewport and charset of the web page and reports the common problems found in them.
+// the canonical and hreflang urls are resolved against the base url of the document (see DocumentBaseURL)
+func (w *webAnalyzerUtilsImpl) DetectSEOMetadata(ctx context.Context, doc *goquery.Document, pageURL *url.URL) response_dtos.SEOAnalysis {
+	seo := response_dtos.SEOAnalysis{
+		Canonical: []string{},
+		Hreflang:  []response_dtos.HreflangLink{},
+		Issues:    []response_dtos.AnalysisIssue{},
+	}
+
+	titles := doc.Find("head title")
+	seo.Title = strings.TrimSpace(titles.First().Text())
+	seo.TitleLength = utf8.RuneCountInString(seo.Title)
+	switch {
+	case seo.Title == "":
+		seo.Issues = append(seo.Issues, newIssue("missing_title", IssueSeverityError, "the page has no title"))
+	case seo.TitleLength > maxSEOTitleLength:
+		seo.Issues = append(seo.Issues, newIssue("title_too_long", IssueSeverityWarning, fmt.Sprintf("the title is %v characters long, more than %v characters may be truncated", seo.TitleLength, maxSEOTitleLength)))
+	}
+	if titles.Length() > 1 {
+		seo.Issues = append(seo.Issues, newIssue("multiple_titles", IssueSeverityWarning, fmt.Sprintf("the page has %v title elements", titles.Length())))
+	}
+
+	descriptions := findMetaByName(doc, "description")
+	seo.MetaDescription = strings.TrimSpace(descriptions.First().AttrOr("content", ""))
+	seo.DescriptionLength = utf8.RuneCountInString(seo.MetaDescription)
+	switch {
+	case seo.MetaDescription == "":
+		seo.Issues = append(seo.Issues, newIssue("missing_description", IssueSeverityWarning, "the page has no meta description"))
+	case seo.DescriptionLength > maxSEODescriptionLength:
+		seo.Issues = append(seo.Issues, newIssue("description_too_long", IssueSeverityWarning, fmt.Sprintf("the meta description is %v characters long, more than %v characters may be truncated", seo.DescriptionLength, maxSEODescriptionLength)))
+	}
+	if descriptions.Length() > 1 {
+		seo.Issues = append(seo.Issues, newIssue("multiple_descriptions", IssueSeverityWarning, fmt.Sprintf("the page has %v meta descriptions", descriptions.Length())))
+	}
+
+	baseURL := DocumentBaseURL(doc, pageURL)
+
+	w.detectCanonical(doc, pageURL, baseURL, &seo)
+	w.detectRobots(doc, &seo)
+	w.detectHreflang(doc, baseURL, &seo)
+
+	seo.Viewport = strings.TrimSpace(findMetaByName(doc, "viewport").First().AttrOr("content", ""))
+	if seo.Viewport == "" {
+		seo.Issues = append(seo.Issues, newIssue("missing_viewport", IssueSeverityWarning, "the page has no viewport meta tag, it may not render well on mobile devices"))
+	}
+
+	seo.Charset = detectCharset(doc)
+	if seo.Charset == "" {
+		seo.Issues = append(seo.Issues, newIssue("missing_charset", IssueSeverityWarning, "the page does not declare a charset"))
+	}
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified %v seo issues", len(seo.Issues)), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+
+	return seo
+}
+
+// detectCanonical - resolves every canonical link against the base url.
+// more than one canonical, invalid urls and canonicals on another host than the page are reported
+func (w *webAnalyzerUtilsImpl) detectCanonical(doc *goquery.Document, pageURL *url.URL, baseURL *url.URL, seo *response_dtos.SEOAnalysis) {
+	doc.Find("link[rel]").Each(func(i int, s *goquery.Selection) {
+		if !hasRel(s, "canonical") {
+			return
+		}
+
+		href := strings.TrimSpace(s.AttrOr("href", ""))
+		canonicalURL, err := url.Parse(href)
+		if href == "" || err != nil {
+			seo.Issues = append(seo.Issues, newIssue("invalid_canonical", IssueSeverityError, fmt.Sprintf("the canonical url %q is not a valid url", href)))
+			return
+		}
+
+		canonicalURL = baseURL.ResolveReference(canonicalURL)
+		seo.Canonical = append(seo.Canonical, canonicalURL.String())
+
+		if !strings.EqualFold(canonicalURL.Hostname(), pageURL.Hostname()) {
+			seo.Issues = append(seo.Issues, newIssue("canonical_off_host", IssueSeverityWarning, fmt.Sprintf("the canonical url %v points to another host", canonicalURL)))
+		}
+	})
+
+	if len(seo.Canonical) > 1 {
+		seo.Issues = append(seo.Issues, newIssue("multiple_canonicals", IssueSeverityError, fmt.Sprintf("the page has %v canonical urls, search engines may ignore all of them", len(seo.Canonical))))
+	}
+}
+
+// detectRobots - splits the robots meta directives and flags noindex and nofollow
+func (w *webAnalyzerUtilsImpl) detectRobots(doc *goquery.Document, seo *response_dtos.SEOAnalysis) {
+	seo.Robots.Directives = []string{}
+
+	var contents []string
+	findMetaByName(doc, "robots").Each(func(i int, s *goquery.Selection) {
+		content := strings.TrimSpace(s.AttrOr("content", ""))
+		if content == "" {
+			return
+		}
+		contents = append(contents, content)
+
+		for _, directive := range strings.Split(content, ",") {
+			directive = strings.ToLower(strings.TrimSpace(directive))
+			if directive == "" {
+				continue
+			}
+			seo.Robots.Directives = append(seo.Robots.Directives, directive)
+
+			switch directive {
+			case "noindex":
+				seo.Robots.NoIndex = true
+			case "nofollow":
+				seo.Robots.NoFollow = true
+			case "none":
+				seo.Robots.NoIndex = true
+				seo.Robots.NoFollow = true
+			}
+		}
+	})
+	seo.Robots.Content = strings.Join(contents, ", ")
+
+	if seo.Robots.NoIndex {
+		seo.Issues = append(seo.Issues, newIssue("noindex", IssueSeverityWarning, "the robots meta tag prevents search engines from indexing the page"))
+	}
+	if seo.Robots.NoFollow {
+		seo.Issues = append(seo.Issues, newIssue("nofollow", IssueSeverityInfo, "the robots meta tag prevents search engines from following the links of the page"))
+	}
+}
+
+// detectHreflang - collects the alternate links with a hreflang attribute and resolves them against the base url.
+// links without a language or url and languages declared more than once are reported
+func (w *webAnalyzerUtilsImpl) detectHreflang(doc *goquery.Document, baseURL *url.URL, seo *response_dtos.SEOAnalysis) {
+	languages := make(map[string]bool)
+
+	doc.Find("link[hreflang]").Each(func(i int, s *goquery.Selection) {
+		if !hasRel(s, "alternate") {
+			return
+		}
+
+		lang := strings.TrimSpace(s.AttrOr("hreflang", ""))
+		href := strings.TrimSpace(s.AttrOr("href", ""))
+		hrefURL, err := url.Parse(href)
+		if lang == "" || href == "" || err != nil {
+			seo.Issues = append(seo.Issues, newIssue("invalid_hreflang", IssueSeverityWarning, fmt.Sprintf("the hreflang alternate %q with url %q is incomplete or invalid", lang, href)))
+			return
+		}
+
+		if languages[strings.ToLower(lang)] {
+			seo.Issues = append(seo.Issues, newIssue("duplicate_hreflang", IssueSeverityWarning, fmt.Sprintf("the hreflang %v is declared more than once", lang)))
+		}
+		languages[strings.ToLower(lang)] = true
+
+		seo.Hreflang = append(seo.Hreflang, response_dtos.HreflangLink{
+			Lang: lang,
+			Href: baseURL.ResolveReference(hrefURL).String(),
+		})
+	})
+}
+
+// detectCharset - returns the charset from <meta charset> or from the content type given in <meta http-equiv>
+func detectCharset(doc *goquery.Document) string {
+	if charset, exists := doc.Find("meta[charset]").First().Attr("charset"); exists {
+		return strings.TrimSpace(charset)
+	}
+
+	charset := ""
+	doc.Find("meta[http-equiv]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if !strings.EqualFold(s.AttrOr("http-equiv", ""), "content-type") {
+			return true
+		}
+		if _, params, err := mime.ParseMediaType(s.AttrOr("content", "")); err == nil {
+			charset = params["charset"]
+		}
+		return false
+	})
+	return charset
+}
+
+// findMetaByName - finds the meta tags with the given name, ignoring the case of the name
+func findMetaByName(doc *goquery.Document, name string) *goquery.Selection {
+	return doc.Find("meta[name]").FilterFunction(func(i int, s *goquery.Selection) bool {
+		return strings.EqualFold(strings.TrimSpace(s.AttrOr("name", "")), name)
+	})
+}
+
+// hasRel - checks if the space separated rel attribute of the element contains the given value
+func hasRel(s *goquery.Selection, rel string) bool {
+	for _, value := range strings.Fields(s.AttrOr("rel", "")) {
+		if strings.EqualFold(value, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func newIssue(code string, severity string, message string) response_dtos.AnalysisIssue {
+	return response_dtos.AnalysisIssue{
+		Code:     code,
+		Severity: severity,
+		Message:  message,
+	}
+}
@@ -0,0 +1,186 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// issueCodes returns the codes of the given issues
+func issueCodes(issues []response_dtos.AnalysisIssue) []string {
+	codes := []string{}
+	for _, issue := range issues {
+		codes = append(codes, issue.Code)
+	}
+	return codes
+}
+
+func TestDetectSEOMetadata(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+	config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1}
+	utils := NewWebAnalyzerUtils(logger, config)
+
+	pageURL, _ := url.Parse("https://example.com/docs/page")
+
+	tests := []struct {
+		name               string
+		html               string
+		expectedTitle      string
+		expectedDesc       string
+		expectedCanonical  []string
+		expectedRobots     response_dtos.RobotsMeta
+		expectedHreflang   []response_dtos.HreflangLink
+		expectedViewport   string
+		expectedCharset    string
+		expectedIssueCodes []string
+	}{
+		{
+			name: "Complete Metadata",
+			html: `<html><head>
+				<meta charset="utf-8">
+				<title>Example Docs</title>
+				<meta name="description" content="Documentation of the example product">
+				<meta name="viewport" content="width=device-width, initial-scale=1">
+				<meta name="robots" content="index, follow">
+				<link rel="canonical" href="/docs/page">
+				<link rel="alternate" hreflang="en" href="https://example.com/docs/page">
+				<link rel="alternate" hreflang="de" href="/de/docs/page">
+				</head><body></body></html>`,
+			expectedTitle:     "Example Docs",
+			expectedDesc:      "Documentation of the example product",
+			expectedCanonical: []string{"https://example.com/docs/page"},
+			expectedRobots: response_dtos.RobotsMeta{
+				Content:    "index, follow",
+				Directives: []string{"index", "follow"},
+			},
+			expectedHreflang: []response_dtos.HreflangLink{
+				{Lang: "en", Href: "https://example.com/docs/page"},
+				{Lang: "de", Href: "https://example.com/de/docs/page"},
+			},
+			expectedViewport:   "width=device-width, initial-scale=1",
+			expectedCharset:    "utf-8",
+			expectedIssueCodes: []string{},
+		},
+		{
+			name:               "Missing Metadata",
+			html:               `<html><head></head><body></body></html>`,
+			expectedCanonical:  []string{},
+			expectedRobots:     response_dtos.RobotsMeta{Directives: []string{}},
+			expectedHreflang:   []response_dtos.HreflangLink{},
+			expectedIssueCodes: []string{"missing_title", "missing_description", "missing_viewport", "missing_charset"},
+		},
+		{
+			name: "Overlong Title and Description",
+			html: `<html><head>
+				<meta http-equiv="Content-Type" content="text/html; charset=ISO-8859-1">
+				<meta name="viewport" content="width=device-width">
+				<title>` + strings.Repeat("t", 61) + `</title>
+				<meta name="Description" content="` + strings.Repeat("d", 161) + `">
+				</head><body></body></html>`,
+			expectedTitle:      strings.Repeat("t", 61),
+			expectedDesc:       strings.Repeat("d", 161),
+			expectedCanonical:  []string{},
+			expectedRobots:     response_dtos.RobotsMeta{Directives: []string{}},
+			expectedHreflang:   []response_dtos.HreflangLink{},
+			expectedViewport:   "width=device-width",
+			expectedCharset:    "ISO-8859-1",
+			expectedIssueCodes: []string{"title_too_long", "description_too_long"},
+		},
+		{
+			name: "Canonical and Hreflang Resolved Against Base Href",
+			html: `<html><head>
+				<base href="https://example.com/en/">
+				<meta charset="utf-8">
+				<meta name="viewport" content="width=device-width">
+				<title>Example</title>
+				<meta name="description" content="Example page">
+				<link rel="canonical" href="guide">
+				<link rel="alternate" hreflang="fr" href="../fr/guide">
+				</head><body></body></html>`,
+			expectedTitle:     "Example",
+			expectedDesc:      "Example page",
+			expectedCanonical: []string{"https://example.com/en/guide"},
+			expectedRobots:    response_dtos.RobotsMeta{Directives: []string{}},
+			expectedHreflang: []response_dtos.HreflangLink{
+				{Lang: "fr", Href: "https://example.com/fr/guide"},
+			},
+			expectedViewport:   "width=device-width",
+			expectedCharset:    "utf-8",
+			expectedIssueCodes: []string{},
+		},
+		{
+			name: "Canonical, Robots and Hreflang Problems",
+			html: `<html><head>
+				<meta charset="utf-8">
+				<meta name="viewport" content="width=device-width">
+				<title>Example</title>
+				<meta name="description" content="Example page">
+				<meta name="robots" content="NOINDEX, nofollow">
+				<link rel="canonical" href="https://example.com/docs/page">
+				<link rel="canonical" href="https://other.com/page">
+				<link rel="alternate" hreflang="en" href="/en">
+				<link rel="alternate" hreflang="EN" href="/en-2">
+				<link rel="alternate" hreflang="" href="/unknown">
+				</head><body></body></html>`,
+			expectedTitle:     "Example",
+			expectedDesc:      "Example page",
+			expectedCanonical: []string{"https://example.com/docs/page", "https://other.com/page"},
+			expectedRobots: response_dtos.RobotsMeta{
+				Content:    "NOINDEX, nofollow",
+				Directives: []string{"noindex", "nofollow"},
+				NoIndex:    true,
+				NoFollow:   true,
+			},
+			expectedHreflang: []response_dtos.HreflangLink{
+				{Lang: "en", Href: "https://example.com/en"},
+				{Lang: "EN", Href: "https://example.com/en-2"},
+			},
+			expectedViewport:   "width=device-width",
+			expectedCharset:    "utf-8",
+			expectedIssueCodes: []string{"canonical_off_host", "multiple_canonicals", "noindex", "nofollow", "duplicate_hreflang", "invalid_hreflang"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatalf("Failed to parse HTML: %v", err)
+			}
+
+			seo := utils.DetectSEOMetadata(context.Background(), doc, pageURL)
+
+			if seo.Title != tt.expectedTitle {
+				t.Errorf("Title = %v, want %v", seo.Title, tt.expectedTitle)
+			}
+			if seo.MetaDescription != tt.expectedDesc {
+				t.Errorf("MetaDescription = %v, want %v", seo.MetaDescription, tt.expectedDesc)
+			}
+			if !reflect.DeepEqual(seo.Canonical, tt.expectedCanonical) {
+				t.Errorf("Canonical = %v, want %v", seo.Canonical, tt.expectedCanonical)
+			}
+			if !reflect.DeepEqual(seo.Robots, tt.expectedRobots) {
+				t.Errorf("Robots = %+v, want %+v", seo.Robots, tt.expectedRobots)
+			}
+			if !reflect.DeepEqual(seo.Hreflang, tt.expectedHreflang) {
+				t.Errorf("Hreflang = %v, want %v", seo.Hreflang, tt.expectedHreflang)
+			}
+			if seo.Viewport != tt.expectedViewport {
+				t.Errorf("Viewport = %v, want %v", seo.Viewport, tt.expectedViewport)
+			}
+			if seo.Charset != tt.expectedCharset {
+				t.Errorf("Charset = %v, want %v", seo.Charset, tt.expectedCharset)
+			}
+			if codes := issueCodes(seo.Issues); !reflect.DeepEqual(codes, tt.expectedIssueCodes) {
+				t.Errorf("Issues = %v, want %v", codes, tt.expectedIssueCodes)
+			}
+		})
+	}
+}
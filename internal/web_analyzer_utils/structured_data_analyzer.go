@@ -0,0 +1,99 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/PuerkitoBio/goquery"
+	"strings"
+)
+
+// jsonLDUntypedKey - group of the JSON-LD objects which do not declare a @type
+const jsonLDUntypedKey = "untyped"
+
+// DetectStructuredData - extracts the Open Graph (og:*) and Twitter Card (twitter:*) meta tags and the
+// JSON-LD blocks of the web page. JSON-LD objects are grouped by their @type and the blocks
+// which cannot be parsed are reported as warnings
+func (w *webAnalyzerUtilsImpl) DetectStructuredData(ctx context.Context, doc *goquery.Document) response_dtos.StructuredData {
+	data := response_dtos.StructuredData{
+		OpenGraph:   make(map[string][]string),
+		TwitterCard: make(map[string][]string),
+		JSONLD:      make(map[string][]map[string]interface{}),
+		Issues:      []response_dtos.AnalysisIssue{},
+	}
+
+	doc.Find("meta").Each(func(i int, s *goquery.Selection) {
+		// open graph uses the property attribute and twitter cards use the name attribute,
+		// but both are commonly found with either of them
+		key := strings.ToLower(strings.TrimSpace(s.AttrOr("property", "")))
+		if key == "" {
+			key = strings.ToLower(strings.TrimSpace(s.AttrOr("name", "")))
+		}
+		content := strings.TrimSpace(s.AttrOr("content", ""))
+
+		switch {
+		case strings.HasPrefix(key, "og:"):
+			data.OpenGraph[key] = append(data.OpenGraph[key], content)
+		case strings.HasPrefix(key, "twitter:"):
+			data.TwitterCard[key] = append(data.TwitterCard[key], content)
+		}
+	})
+
+	doc.Find("script[type]").Each(func(i int, s *goquery.Selection) {
+		mediaType := strings.TrimSpace(strings.Split(s.AttrOr("type", ""), ";")[0])
+		if !strings.EqualFold(mediaType, "application/ld+json") {
+			return
+		}
+		data.JSONLDBlocks++
+
+		var block interface{}
+		if err := json.Unmarshal([]byte(s.Text()), &block); err != nil {
+			data.Issues = append(data.Issues, newIssue("invalid_json_ld", IssueSeverityWarning, fmt.Sprintf("JSON-LD block %v cannot be parsed: %v", data.JSONLDBlocks, err)))
+			return
+		}
+		groupJSONLD(block, data.JSONLD)
+	})
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified %v open graph tags, %v twitter card tags and %v JSON-LD blocks", len(data.OpenGraph), len(data.TwitterCard), data.JSONLDBlocks), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+
+	return data
+}
+
+// groupJSONLD - adds the JSON-LD objects in the parsed block to the groups of their @type.
+// arrays and @graph containers are flattened, objects with multiple types are added to each group
+func groupJSONLD(block interface{}, groups map[string][]map[string]interface{}) {
+	switch value := block.(type) {
+	case []interface{}:
+		for _, item := range value {
+			groupJSONLD(item, groups)
+		}
+	case map[string]interface{}:
+		if graph, ok := value["@graph"]; ok {
+			groupJSONLD(graph, groups)
+			if _, typed := value["@type"]; !typed {
+				return
+			}
+		}
+
+		var types []string
+		switch jsonLDType := value["@type"].(type) {
+		case string:
+			types = append(types, jsonLDType)
+		case []interface{}:
+			for _, t := range jsonLDType {
+				if typeName, ok := t.(string); ok {
+					types = append(types, typeName)
+				}
+			}
+		}
+		if len(types) == 0 {
+			types = append(types, jsonLDUntypedKey)
+		}
+
+		for _, typeName := range types {
+			groups[typeName] = append(groups[typeName], value)
+		}
+	}
+}
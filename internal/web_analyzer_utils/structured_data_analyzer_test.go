@@ -0,0 +1,108 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestDetectStructuredData(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+	config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1}
+	utils := NewWebAnalyzerUtils(logger, config)
+
+	tests := []struct {
+		name               string
+		html               string
+		expectedOpenGraph  map[string][]string
+		expectedTwitter    map[string][]string
+		expectedTypes      map[string]int
+		expectedBlocks     int
+		expectedIssueCodes []string
+	}{
+		{
+			name: "Open Graph and Twitter Card Tags",
+			html: `<html><head>
+				<meta property="og:title" content="Example">
+				<meta property="OG:image" content="https://example.com/a.png">
+				<meta property="og:image" content="https://example.com/b.png">
+				<meta name="twitter:card" content="summary_large_image">
+				<meta property="twitter:site" content="@example">
+				<meta name="description" content="not structured data">
+				</head><body></body></html>`,
+			expectedOpenGraph: map[string][]string{
+				"og:title": {"Example"},
+				"og:image": {"https://example.com/a.png", "https://example.com/b.png"},
+			},
+			expectedTwitter: map[string][]string{
+				"twitter:card": {"summary_large_image"},
+				"twitter:site": {"@example"},
+			},
+			expectedTypes:      map[string]int{},
+			expectedIssueCodes: []string{},
+		},
+		{
+			name: "JSON-LD Grouped by Type",
+			html: `<html><head>
+				<script type="application/ld+json">{"@context": "https://schema.org", "@type": "Organization", "name": "Example"}</script>
+				<script type="application/ld+json">[{"@type": "Product", "name": "A"}, {"@type": ["Product", "Offer"], "name": "B"}]</script>
+				<script type="application/ld+json; charset=utf-8">{"@context": "https://schema.org", "@graph": [{"@type": "WebSite"}, {"name": "no type"}]}</script>
+				<script type="text/javascript">var a = 1;</script>
+				</head><body></body></html>`,
+			expectedOpenGraph:  map[string][]string{},
+			expectedTwitter:    map[string][]string{},
+			expectedTypes:      map[string]int{"Organization": 1, "Product": 2, "Offer": 1, "WebSite": 1, "untyped": 1},
+			expectedBlocks:     3,
+			expectedIssueCodes: []string{},
+		},
+		{
+			name: "Invalid JSON-LD",
+			html: `<html><head>
+				<script type="application/ld+json">{"@type": "Article", "headline": "Broken",}</script>
+				<script type="application/ld+json">{"@type": "Article", "headline": "Valid"}</script>
+				</head><body></body></html>`,
+			expectedOpenGraph:  map[string][]string{},
+			expectedTwitter:    map[string][]string{},
+			expectedTypes:      map[string]int{"Article": 1},
+			expectedBlocks:     2,
+			expectedIssueCodes: []string{"invalid_json_ld"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatalf("Failed to parse HTML: %v", err)
+			}
+
+			data := utils.DetectStructuredData(context.Background(), doc)
+
+			if !reflect.DeepEqual(data.OpenGraph, tt.expectedOpenGraph) {
+				t.Errorf("OpenGraph = %v, want %v", data.OpenGraph, tt.expectedOpenGraph)
+			}
+			if !reflect.DeepEqual(data.TwitterCard, tt.expectedTwitter) {
+				t.Errorf("TwitterCard = %v, want %v", data.TwitterCard, tt.expectedTwitter)
+			}
+
+			types := make(map[string]int)
+			for typeName, objects := range data.JSONLD {
+				types[typeName] = len(objects)
+			}
+			if !reflect.DeepEqual(types, tt.expectedTypes) {
+				t.Errorf("JSON-LD types = %v, want %v", types, tt.expectedTypes)
+			}
+			if data.JSONLDBlocks != tt.expectedBlocks {
+				t.Errorf("JSONLDBlocks = %v, want %v", data.JSONLDBlocks, tt.expectedBlocks)
+			}
+			if codes := issueCodes(data.Issues); !reflect.DeepEqual(codes, tt.expectedIssueCodes) {
+				t.Errorf("Issues = %v, want %v", codes, tt.expectedIssueCodes)
+			}
+		})
+	}
+}
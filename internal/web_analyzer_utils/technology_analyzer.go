@@ -0,0 +1,138 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/PuerkitoBio/goquery"
+	"net/http"
+	"strings"
+)
+
+// pageSignals - values of the web page the technology rules are matched against
+type pageSignals struct {
+	doc           *goquery.Document
+	header        http.Header
+	meta          map[string][]string
+	scripts       []string
+	inlineScripts []string
+	cookies       []string
+}
+
+// DetectTechnologies - detects the technologies the web page is built with by matching the meta tags, scripts,
+// response headers, cookies and elements of the page against the technology rules.
+// technologies are reported in the order of the rules file with the summed confidence of the matching rules
+// and the version when a matching pattern captures it
+func (w *webAnalyzerUtilsImpl) DetectTechnologies(ctx context.Context, doc *goquery.Document, header http.Header) []response_dtos.Technology {
+	signals := collectPageSignals(doc, header)
+	technologies := []response_dtos.Technology{}
+
+	for _, tech := range w.technologies {
+		detected := response_dtos.Technology{
+			Name:     tech.name,
+			Category: tech.category,
+			Evidence: []string{},
+		}
+
+		for _, rule := range tech.rules {
+			matched, version, evidence := matchTechnologyRule(rule, signals)
+			if !matched {
+				continue
+			}
+			detected.Confidence += rule.confidence
+			detected.Evidence = append(detected.Evidence, evidence)
+			if detected.Version == "" {
+				detected.Version = version
+			}
+		}
+
+		if detected.Confidence == 0 {
+			continue
+		}
+		if detected.Confidence > maxTechnologyConfidence {
+			detected.Confidence = maxTechnologyConfidence
+		}
+		technologies = append(technologies, detected)
+	}
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified %v technologies", len(technologies)), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+
+	return technologies
+}
+
+func collectPageSignals(doc *goquery.Document, header http.Header) pageSignals {
+	signals := pageSignals{
+		doc:    doc,
+		header: header,
+		meta:   make(map[string][]string),
+	}
+
+	doc.Find("meta[name]").Each(func(i int, s *goquery.Selection) {
+		name := strings.ToLower(strings.TrimSpace(s.AttrOr("name", "")))
+		signals.meta[name] = append(signals.meta[name], strings.TrimSpace(s.AttrOr("content", "")))
+	})
+
+	doc.Find("script").Each(func(i int, s *goquery.Selection) {
+		if src, exists := s.Attr("src"); exists {
+			signals.scripts = append(signals.scripts, strings.TrimSpace(src))
+		} else if text := strings.TrimSpace(s.Text()); text != "" {
+			signals.inlineScripts = append(signals.inlineScripts, text)
+		}
+	})
+
+	for _, cookie := range (&http.Response{Header: header}).Cookies() {
+		signals.cookies = append(signals.cookies, cookie.Name)
+	}
+
+	return signals
+}
+
+// matchTechnologyRule - matches the rule against the values of its type and returns the version captured by
+// the first matching value along with a short description of what matched
+func matchTechnologyRule(rule technologyRule, signals pageSignals) (bool, string, string) {
+	var values []string
+	var evidence string
+
+	switch rule.ruleType {
+	case technologyRuleMeta:
+		values = signals.meta[rule.key]
+		evidence = fmt.Sprintf("meta %v", rule.key)
+	case technologyRuleScript:
+		values = signals.scripts
+		evidence = "script"
+	case technologyRuleInline:
+		values = signals.inlineScripts
+		evidence = "inline script"
+	case technologyRuleHeader:
+		values = signals.header.Values(rule.key)
+		evidence = fmt.Sprintf("header %v", rule.key)
+	case technologyRuleCookie:
+		values = signals.cookies
+		evidence = "cookie"
+	case technologyRuleSelector:
+		signals.doc.Find(rule.key).Each(func(i int, s *goquery.Selection) {
+			values = append(values, strings.TrimSpace(s.AttrOr(rule.attribute, "")))
+		})
+		evidence = fmt.Sprintf("element %v", rule.key)
+	}
+
+	for _, value := range values {
+		match := rule.pattern.FindStringSubmatch(value)
+		if match == nil {
+			continue
+		}
+
+		switch rule.ruleType {
+		case technologyRuleScript, technologyRuleCookie:
+			evidence = fmt.Sprintf("%v %v", evidence, value)
+		}
+		for _, group := range match[1:] {
+			if group != "" {
+				return true, group, evidence
+			}
+		}
+		return true, "", evidence
+	}
+	return false, "", ""
+}
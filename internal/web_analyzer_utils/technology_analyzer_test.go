@@ -0,0 +1,140 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestDetectTechnologies(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+	config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1}
+	utils := NewWebAnalyzerUtils(logger, config)
+
+	tests := []struct {
+		name     string
+		html     string
+		header   http.Header
+		expected []response_dtos.Technology
+	}{
+		{
+			name: "WordPress site with jQuery and Google Analytics behind nginx",
+			html: `<html><head>
+				<meta name="generator" content="WordPress 6.4.2">
+				<script src="/wp-includes/js/jquery/jquery.min.js?ver=3.7.1"></script>
+				<script src="https://www.googletagmanager.com/gtag/js?id=G-1"></script>
+				<script>gtag('config', 'G-1');</script>
+				</head><body></body></html>`,
+			header: http.Header{
+				"Server":       {"nginx/1.25.3"},
+				"X-Powered-By": {"PHP/8.2.1"},
+				"Set-Cookie":   {"wordpress_test_cookie=WP%20Cookie%20check; path=/"},
+			},
+			expected: []response_dtos.Technology{
+				{Name: "WordPress", Category: "cms", Version: "6.4.2", Confidence: 100, Evidence: []string{"meta generator", "script /wp-includes/js/jquery/jquery.min.js?ver=3.7.1", "cookie wordpress_test_cookie"}},
+				{Name: "jQuery", Category: "javascript_library", Confidence: 100, Evidence: []string{"script /wp-includes/js/jquery/jquery.min.js?ver=3.7.1"}},
+				{Name: "Google Analytics", Category: "analytics", Confidence: 100, Evidence: []string{"script https://www.googletagmanager.com/gtag/js?id=G-1", "inline script"}},
+				{Name: "nginx", Category: "web_server", Version: "1.25.3", Confidence: 100, Evidence: []string{"header Server"}},
+				{Name: "PHP", Category: "programming_language", Version: "8.2.1", Confidence: 100, Evidence: []string{"header X-Powered-By"}},
+			},
+		},
+		{
+			name: "Next.js app on Vercel",
+			html: `<html><head>
+				<script src="/_next/static/chunks/main.js" defer></script>
+				</head><body><div id="__next"></div>
+				<script id="__NEXT_DATA__" type="application/json">{"buildId": "1"}</script>
+				</body></html>`,
+			header: http.Header{
+				"Server":      {"Vercel"},
+				"X-Vercel-Id": {"fra1::abc"},
+			},
+			expected: []response_dtos.Technology{
+				{Name: "Next.js", Category: "javascript_framework", Confidence: 100, Evidence: []string{"element script#__NEXT_DATA__", "script /_next/static/chunks/main.js"}},
+				{Name: "Vercel", Category: "paas", Confidence: 100, Evidence: []string{"header Server", "header X-Vercel-Id"}},
+			},
+		},
+		{
+			name: "Angular version and partial confidence",
+			html: `<html><body>
+				<app-root ng-version="17.0.8"></app-root>
+				<script src="https://code.jquery.com/jquery-3.6.0.slim.min.js"></script>
+				<script src="https://cdn.jsdelivr.net/npm/bootstrap@5.3.2/dist/js/bootstrap.bundle.min.js"></script>
+				</body></html>`,
+			header: http.Header{
+				"Set-Cookie": {"__cf_bm=abc; path=/; secure; HttpOnly"},
+			},
+			expected: []response_dtos.Technology{
+				{Name: "Angular", Category: "javascript_framework", Version: "17.0.8", Confidence: 100, Evidence: []string{"element [ng-version]"}},
+				{Name: "jQuery", Category: "javascript_library", Version: "3.6.0", Confidence: 100, Evidence: []string{"script https://code.jquery.com/jquery-3.6.0.slim.min.js"}},
+				{Name: "Bootstrap", Category: "ui_framework", Version: "5.3.2", Confidence: 100, Evidence: []string{"script https://cdn.jsdelivr.net/npm/bootstrap@5.3.2/dist/js/bootstrap.bundle.min.js"}},
+				{Name: "Cloudflare", Category: "cdn", Confidence: 50, Evidence: []string{"cookie __cf_bm"}},
+			},
+		},
+		{
+			name:     "Nothing detected",
+			html:     `<html><head><title>plain</title></head><body><p>hello</p></body></html>`,
+			header:   http.Header{},
+			expected: []response_dtos.Technology{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatalf("Failed to parse HTML: %v", err)
+			}
+
+			technologies := utils.DetectTechnologies(context.Background(), doc, tt.header)
+
+			if !reflect.DeepEqual(technologies, tt.expected) {
+				t.Errorf("DetectTechnologies() = %+v, want %+v", technologies, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseTechnologyRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     string
+		expectErr bool
+	}{
+		{
+			name:  "Valid rules",
+			rules: "technologies:\n  - name: Example\n    rules:\n      - {type: header, key: Server, pattern: '^example'}\n",
+		},
+		{
+			name:      "Unknown rule type",
+			rules:     "technologies:\n  - name: Example\n    rules:\n      - {type: body, pattern: 'example'}\n",
+			expectErr: true,
+		},
+		{
+			name:      "Invalid pattern",
+			rules:     "technologies:\n  - name: Example\n    rules:\n      - {type: script, pattern: '(example'}\n",
+			expectErr: true,
+		},
+		{
+			name:      "Header rule without key",
+			rules:     "technologies:\n  - name: Example\n    rules:\n      - {type: header, pattern: 'example'}\n",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTechnologyRules([]byte(tt.rules))
+			if (err != nil) != tt.expectErr {
+				t.Errorf("parseTechnologyRules() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package web_analyzer_utils
+
+import (
+	_ "embed"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+//go:embed technologies.yaml
+var defaultTechnologyRules []byte
+
+// types of the technology fingerprint rules
+const (
+	technologyRuleMeta     = "meta"
+	technologyRuleScript   = "script"
+	technologyRuleInline   = "inline"
+	technologyRuleHeader   = "header"
+	technologyRuleCookie   = "cookie"
+	technologyRuleSelector = "selector"
+)
+
+const maxTechnologyConfidence = 100
+
+type technologyRulesFile struct {
+	Technologies []struct {
+		Name     string `yaml:"name"`
+		Category string `yaml:"category"`
+		Rules    []struct {
+			Type       string `yaml:"type"`
+			Key        string `yaml:"key"`
+			Attribute  string `yaml:"attribute"`
+			Pattern    string `yaml:"pattern"`
+			Confidence int    `yaml:"confidence"`
+		} `yaml:"rules"`
+	} `yaml:"technologies"`
+}
+
+// technology - a technology with its compiled fingerprint rules
+type technology struct {
+	name     string
+	category string
+	rules    []technologyRule
+}
+
+type technologyRule struct {
+	ruleType   string
+	key        string
+	attribute  string
+	pattern    *regexp.Regexp
+	confidence int
+}
+
+// loadTechnologyRules - reads the technology rules from the given path, or the embedded rules when the path is empty
+func loadTechnologyRules(path string) ([]technology, error) {
+	if path == "" {
+		return parseTechnologyRules(defaultTechnologyRules)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseTechnologyRules(data)
+}
+
+// parseTechnologyRules - parses the yaml technology rules and compiles their patterns.
+// a rule with an unknown type or an invalid pattern fails the whole file
+func parseTechnologyRules(data []byte) ([]technology, error) {
+	var file technologyRulesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid technology rules: %w", err)
+	}
+
+	technologies := make([]technology, 0, len(file.Technologies))
+	for _, definition := range file.Technologies {
+		if definition.Name == "" {
+			return nil, fmt.Errorf("invalid technology rules: a technology has no name")
+		}
+
+		tech := technology{name: definition.Name, category: definition.Category}
+		for _, ruleDefinition := range definition.Rules {
+			switch ruleDefinition.Type {
+			case technologyRuleMeta, technologyRuleHeader, technologyRuleSelector:
+				if ruleDefinition.Key == "" {
+					return nil, fmt.Errorf("invalid technology rules: the %v rule of %v has no key", ruleDefinition.Type, definition.Name)
+				}
+			case technologyRuleScript, technologyRuleInline, technologyRuleCookie:
+			default:
+				return nil, fmt.Errorf("invalid technology rules: unknown rule type %q in %v", ruleDefinition.Type, definition.Name)
+			}
+
+			pattern, err := regexp.Compile(ruleDefinition.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid technology rules: the pattern of %v: %w", definition.Name, err)
+			}
+
+			rule := technologyRule{
+				ruleType:   ruleDefinition.Type,
+				key:        ruleDefinition.Key,
+				attribute:  ruleDefinition.Attribute,
+				pattern:    pattern,
+				confidence: ruleDefinition.Confidence,
+			}
+			switch rule.ruleType {
+			case technologyRuleMeta:
+				rule.key = strings.ToLower(rule.key)
+			case technologyRuleHeader:
+				rule.key = http.CanonicalHeaderKey(rule.key)
+			}
+			if rule.confidence <= 0 || rule.confidence > maxTechnologyConfidence {
+				rule.confidence = maxTechnologyConfidence
+			}
+			tech.rules = append(tech.rules, rule)
+		}
+		technologies = append(technologies, tech)
+	}
+	return technologies, nil
+}
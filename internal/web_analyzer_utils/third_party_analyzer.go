@@ -0,0 +1,110 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"golang.org/x/net/publicsuffix"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DetectThirdPartyDomains - groups the links and the external resources of the web page by their registrable
+// domain (eTLD+1) and reports every domain other than the domain of the page with its reference counts.
+// each domain is matched against the tracker list to find out its category.
+// domains are ordered by the total number of references, the most referenced first
+func (w *webAnalyzerUtilsImpl) DetectThirdPartyDomains(ctx context.Context, pageURL *url.URL, links []string, inventory response_dtos.ResourceInventory) response_dtos.ThirdPartyAnalysis {
+	analysis := response_dtos.ThirdPartyAnalysis{
+		FirstPartyDomain: registrableDomain(pageURL.Hostname()),
+		Domains:          []response_dtos.ThirdPartyDomain{},
+		Categories:       make(map[string]int),
+	}
+
+	domains := make(map[string]*response_dtos.ThirdPartyDomain)
+	hosts := make(map[string]map[string]bool)
+
+	addReference := func(reference string, isResource bool) {
+		ref, err := url.Parse(strings.TrimSpace(reference))
+		if err != nil {
+			return
+		}
+		referenceURL := pageURL.ResolveReference(ref)
+		if referenceURL.Scheme != "http" && referenceURL.Scheme != "https" {
+			return
+		}
+
+		host := strings.TrimSuffix(strings.ToLower(referenceURL.Hostname()), ".")
+		domain := registrableDomain(host)
+		if host == "" || domain == analysis.FirstPartyDomain {
+			return
+		}
+
+		thirdParty, ok := domains[domain]
+		if !ok {
+			thirdParty = &response_dtos.ThirdPartyDomain{Domain: domain}
+			domains[domain] = thirdParty
+			hosts[domain] = make(map[string]bool)
+		}
+		if !hosts[domain][host] {
+			hosts[domain][host] = true
+			thirdParty.Hosts = append(thirdParty.Hosts, host)
+		}
+		if category := lookupDomain(w.trackers, host); category != "" && thirdParty.Category == "" {
+			thirdParty.Category = category
+			thirdParty.Tracker = true
+		}
+
+		if isResource {
+			thirdParty.ResourceReferences++
+		} else {
+			thirdParty.LinkReferences++
+		}
+		thirdParty.TotalReferences++
+	}
+
+	for _, link := range links {
+		addReference(link, false)
+	}
+	for _, resource := range inventory.Resources {
+		if !resource.Inline {
+			addReference(resource.Url, true)
+		}
+	}
+
+	for _, thirdParty := range domains {
+		sort.Strings(thirdParty.Hosts)
+		if thirdParty.Tracker {
+			analysis.TrackerCount++
+			analysis.Categories[thirdParty.Category]++
+		}
+		analysis.Domains = append(analysis.Domains, *thirdParty)
+	}
+	sort.Slice(analysis.Domains, func(i, j int) bool {
+		if analysis.Domains[i].TotalReferences != analysis.Domains[j].TotalReferences {
+			return analysis.Domains[i].TotalReferences > analysis.Domains[j].TotalReferences
+		}
+		return analysis.Domains[i].Domain < analysis.Domains[j].Domain
+	})
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified %v third-party domains, %v of them known trackers", len(analysis.Domains), analysis.TrackerCount), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+
+	return analysis
+}
+
+// registrableDomain - returns the registrable domain (eTLD+1) of the host using the public suffix list.
+// ip addresses, single label hosts and public suffixes themselves are returned as they are
+func registrableDomain(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if net.ParseIP(host) != nil {
+		return host
+	}
+
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return host
+	}
+	return domain
+}
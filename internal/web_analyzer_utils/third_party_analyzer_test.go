@@ -0,0 +1,109 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+)
+
+func TestDetectThirdPartyDomains(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+
+	customList := filepath.Join(t.TempDir(), "trackers.yaml")
+	if err := os.WriteFile(customList, []byte("analytics:\n  - stats.example.org\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write the tracker list: %v", err)
+	}
+
+	pageURL, _ := url.Parse("https://www.example.co.uk/blog/")
+	links := []string{
+		"/about",
+		"https://shop.example.co.uk/cart",
+		"https://www.facebook.com/example",
+		"https://facebook.com/share",
+		"https://stats.example.org/visit",
+		"mailto:hello@example.co.uk",
+		"https://192.0.2.1/status",
+	}
+	inventory := response_dtos.ResourceInventory{
+		Resources: []response_dtos.Resource{
+			{Type: "script", Url: "https://www.google-analytics.com/analytics.js"},
+			{Type: "script", Url: "https://connect.facebook.net/en_US/sdk.js"},
+			{Type: "script", Inline: true},
+			{Type: "image", Url: "https://www.example.co.uk/logo.png"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		config   *configurations.WebAnalyzerConfigurations
+		expected response_dtos.ThirdPartyAnalysis
+	}{
+		{
+			name:   "Embedded tracker list",
+			config: &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1},
+			expected: response_dtos.ThirdPartyAnalysis{
+				FirstPartyDomain: "example.co.uk",
+				Domains: []response_dtos.ThirdPartyDomain{
+					{Domain: "facebook.com", Hosts: []string{"facebook.com", "www.facebook.com"}, Category: "social", Tracker: true, LinkReferences: 2, TotalReferences: 2},
+					{Domain: "192.0.2.1", Hosts: []string{"192.0.2.1"}, LinkReferences: 1, TotalReferences: 1},
+					{Domain: "example.org", Hosts: []string{"stats.example.org"}, LinkReferences: 1, TotalReferences: 1},
+					{Domain: "facebook.net", Hosts: []string{"connect.facebook.net"}, Category: "social", Tracker: true, ResourceReferences: 1, TotalReferences: 1},
+					{Domain: "google-analytics.com", Hosts: []string{"www.google-analytics.com"}, Category: "analytics", Tracker: true, ResourceReferences: 1, TotalReferences: 1},
+				},
+				Categories:   map[string]int{"social": 2, "analytics": 1},
+				TrackerCount: 3,
+			},
+		},
+		{
+			name:   "Tracker list from the configured path",
+			config: &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1, TrackerListPath: customList},
+			expected: response_dtos.ThirdPartyAnalysis{
+				FirstPartyDomain: "example.co.uk",
+				Domains: []response_dtos.ThirdPartyDomain{
+					{Domain: "facebook.com", Hosts: []string{"facebook.com", "www.facebook.com"}, LinkReferences: 2, TotalReferences: 2},
+					{Domain: "192.0.2.1", Hosts: []string{"192.0.2.1"}, LinkReferences: 1, TotalReferences: 1},
+					{Domain: "example.org", Hosts: []string{"stats.example.org"}, Category: "analytics", Tracker: true, LinkReferences: 1, TotalReferences: 1},
+					{Domain: "facebook.net", Hosts: []string{"connect.facebook.net"}, ResourceReferences: 1, TotalReferences: 1},
+					{Domain: "google-analytics.com", Hosts: []string{"www.google-analytics.com"}, ResourceReferences: 1, TotalReferences: 1},
+				},
+				Categories:   map[string]int{"analytics": 1},
+				TrackerCount: 1,
+			},
+		},
+		{
+			name:   "Missing tracker list falls back to the embedded list",
+			config: &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1, TrackerListPath: filepath.Join(t.TempDir(), "missing.yaml")},
+			expected: response_dtos.ThirdPartyAnalysis{
+				FirstPartyDomain: "example.co.uk",
+				Domains: []response_dtos.ThirdPartyDomain{
+					{Domain: "facebook.com", Hosts: []string{"facebook.com", "www.facebook.com"}, Category: "social", Tracker: true, LinkReferences: 2, TotalReferences: 2},
+					{Domain: "192.0.2.1", Hosts: []string{"192.0.2.1"}, LinkReferences: 1, TotalReferences: 1},
+					{Domain: "example.org", Hosts: []string{"stats.example.org"}, LinkReferences: 1, TotalReferences: 1},
+					{Domain: "facebook.net", Hosts: []string{"connect.facebook.net"}, Category: "social", Tracker: true, ResourceReferences: 1, TotalReferences: 1},
+					{Domain: "google-analytics.com", Hosts: []string{"www.google-analytics.com"}, Category: "analytics", Tracker: true, ResourceReferences: 1, TotalReferences: 1},
+				},
+				Categories:   map[string]int{"social": 2, "analytics": 1},
+				TrackerCount: 3,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utils := NewWebAnalyzerUtils(logger, tt.config)
+
+			analysis := utils.DetectThirdPartyDomains(context.Background(), pageURL, links, inventory)
+
+			if !reflect.DeepEqual(analysis, tt.expected) {
+				t.Errorf("DetectThirdPartyDomains() = %+v, want %+v", analysis, tt.expected)
+			}
+		})
+	}
+}
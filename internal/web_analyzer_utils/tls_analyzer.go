@@ -0,0 +1,138 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	"time"
+)
+
+// DetectTLSDetails - reports the negotiated tls version and cipher suite, and the subject, SANs, issuer chain and
+// expiry of the certificate of the connection. expired certificates, certificates expiring within
+// webAnalyzerConfig.CertificateExpiryWarningDays, certificates not matching the host, certificates not issued
+// by a trusted authority and tls versions older than 1.2 are reported. the page is fetched without verifying
+// its certificate, so the chain is verified here when the connection did not verify it.
+// a nil state means the page was not served over tls
+func (w *webAnalyzerUtilsImpl) DetectTLSDetails(ctx context.Context, state *tls.ConnectionState, host string) response_dtos.TLSAnalysis {
+	analysis := response_dtos.TLSAnalysis{
+		Issues: []response_dtos.AnalysisIssue{},
+	}
+	if state == nil {
+		w.logger.InfoWithContext(ctx, "the page is not served over tls", log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+		return analysis
+	}
+
+	analysis.Enabled = true
+	analysis.Version = tls.VersionName(state.Version)
+	analysis.CipherSuite = tls.CipherSuiteName(state.CipherSuite)
+	if state.Version < tls.VersionTLS12 {
+		analysis.Issues = append(analysis.Issues, newIssue("legacy_tls_version", IssueSeverityWarning, fmt.Sprintf("%v is deprecated, use TLS 1.2 or newer", analysis.Version)))
+	}
+
+	if len(state.PeerCertificates) == 0 {
+		analysis.Issues = append(analysis.Issues, newIssue("missing_certificate", IssueSeverityError, "the server did not present a certificate"))
+		return analysis
+	}
+
+	leaf := state.PeerCertificates[0]
+	analysis.Subject = leaf.Subject.String()
+	analysis.Issuer = leaf.Issuer.String()
+	analysis.NotBefore = &leaf.NotBefore
+	analysis.NotAfter = &leaf.NotAfter
+	analysis.SANs = certificateSANs(leaf)
+	analysis.DaysUntilExpiry = int(time.Until(leaf.NotAfter).Hours() / 24)
+
+	// the verified chain includes the trusted root, which the server usually does not send
+	verifiedChains, err := verifiedCertificateChains(state)
+	analysis.Trusted = err == nil
+	chain := state.PeerCertificates
+	if len(verifiedChains) > 0 {
+		chain = verifiedChains[0]
+	}
+	for _, certificate := range chain {
+		analysis.Chain = append(analysis.Chain, response_dtos.TLSCertificate{
+			Subject:  certificate.Subject.String(),
+			Issuer:   certificate.Issuer.String(),
+			NotAfter: certificate.NotAfter,
+		})
+	}
+
+	if !analysis.Trusted {
+		analysis.Issues = append(analysis.Issues, newIssue("untrusted_certificate", IssueSeverityError, fmt.Sprintf("the certificate is not trusted: %v", err)))
+	}
+
+	analysis.HostnameMatch = leaf.VerifyHostname(host) == nil
+	if !analysis.HostnameMatch {
+		analysis.Issues = append(analysis.Issues, newIssue("hostname_mismatch", IssueSeverityError, fmt.Sprintf("the certificate is not valid for %v", host)))
+	}
+
+	warningDays := w.webAnalyzerConfig.CertificateExpiryWarningDays
+	switch {
+	case time.Now().After(leaf.NotAfter):
+		analysis.Issues = append(analysis.Issues, newIssue("certificate_expired", IssueSeverityError, fmt.Sprintf("the certificate expired on %v", leaf.NotAfter.Format(time.DateOnly))))
+	case warningDays > 0 && analysis.DaysUntilExpiry < warningDays:
+		analysis.Issues = append(analysis.Issues, newIssue("certificate_expiring", IssueSeverityWarning, fmt.Sprintf("the certificate expires in %v days", analysis.DaysUntilExpiry)))
+	}
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified %v with a certificate expiring in %v days", analysis.Version, analysis.DaysUntilExpiry), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+
+	return analysis
+}
+
+// VerifyCertificate - checks that the certificate of the connection is trusted, valid now and issued for the host.
+// it is used for the redirect hops, whose certificates are not verified while fetching the page and are not
+// analyzed by DetectTLSDetails. a nil state means the response was not served over tls and is not checked
+func VerifyCertificate(state *tls.ConnectionState, host string) error {
+	if state == nil {
+		return nil
+	}
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("the server did not present a certificate")
+	}
+
+	leaf := state.PeerCertificates[0]
+	if len(state.VerifiedChains) > 0 {
+		return leaf.VerifyHostname(host)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, certificate := range state.PeerCertificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{DNSName: host, Intermediates: intermediates})
+	return err
+}
+
+// certificateSANs - returns the dns names and ip addresses the certificate is issued for
+func certificateSANs(certificate *x509.Certificate) []string {
+	sans := append([]string{}, certificate.DNSNames...)
+	for _, ip := range certificate.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return sans
+}
+
+// verifiedCertificateChains - returns the chains the connection verified or, when the connection skipped the
+// verification, verifies the certificates the server sent against the system roots. the chain is verified at a
+// time the leaf is valid at, since an expired certificate is reported on its own
+func verifiedCertificateChains(state *tls.ConnectionState) ([][]*x509.Certificate, error) {
+	if len(state.VerifiedChains) > 0 {
+		return state.VerifiedChains, nil
+	}
+
+	leaf := state.PeerCertificates[0]
+	intermediates := x509.NewCertPool()
+	for _, certificate := range state.PeerCertificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+
+	verifyTime := time.Now()
+	if verifyTime.After(leaf.NotAfter) {
+		verifyTime = leaf.NotAfter
+	}
+	return leaf.Verify(x509.VerifyOptions{Intermediates: intermediates, CurrentTime: verifyTime})
+}
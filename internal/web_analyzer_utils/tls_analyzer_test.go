@@ -0,0 +1,266 @@
+package web_analyzer_utils
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DaminduDilsara/web-analyzer/configurations"
+	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+)
+
+func TestDetectTLSDetails(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to connect to the tls server: %v", err)
+	}
+	resp.Body.Close()
+
+	tests := []struct {
+		name               string
+		state              *tls.ConnectionState
+		host               string
+		warningDays        int
+		expectedEnabled    bool
+		expectedMatch      bool
+		expectedIssueCodes []string
+	}{
+		{
+			name:               "Valid Certificate",
+			state:              resp.TLS,
+			host:               "example.com",
+			warningDays:        30,
+			expectedEnabled:    true,
+			expectedMatch:      true,
+			expectedIssueCodes: []string{},
+		},
+		{
+			name:               "Hostname Mismatch",
+			state:              resp.TLS,
+			host:               "other.test",
+			expectedEnabled:    true,
+			expectedMatch:      false,
+			expectedIssueCodes: []string{"hostname_mismatch"},
+		},
+		{
+			name:               "Certificate Expiring Within The Window",
+			state:              resp.TLS,
+			host:               "127.0.0.1",
+			warningDays:        100 * 365,
+			expectedEnabled:    true,
+			expectedMatch:      true,
+			expectedIssueCodes: []string{"certificate_expiring"},
+		},
+		{
+			name:               "Not Served Over TLS",
+			state:              nil,
+			host:               "example.com",
+			expectedEnabled:    false,
+			expectedMatch:      false,
+			expectedIssueCodes: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1, CertificateExpiryWarningDays: tt.warningDays}
+			utils := NewWebAnalyzerUtils(logger, config)
+
+			analysis := utils.DetectTLSDetails(context.Background(), tt.state, tt.host)
+
+			if analysis.Enabled != tt.expectedEnabled {
+				t.Errorf("Enabled = %v, want %v", analysis.Enabled, tt.expectedEnabled)
+			}
+			if analysis.HostnameMatch != tt.expectedMatch {
+				t.Errorf("HostnameMatch = %v, want %v", analysis.HostnameMatch, tt.expectedMatch)
+			}
+			if analysis.Trusted != tt.expectedEnabled {
+				t.Errorf("Trusted = %v, want %v", analysis.Trusted, tt.expectedEnabled)
+			}
+			if codes := issueCodes(analysis.Issues); !reflect.DeepEqual(codes, tt.expectedIssueCodes) {
+				t.Errorf("Issues = %v, want %v", codes, tt.expectedIssueCodes)
+			}
+
+			if !tt.expectedEnabled {
+				return
+			}
+			if analysis.Version != tls.VersionName(tt.state.Version) || analysis.CipherSuite == "" {
+				t.Errorf("Version = %v, CipherSuite = %v", analysis.Version, analysis.CipherSuite)
+			}
+			if !reflect.DeepEqual(analysis.SANs, []string{"example.com", "*.example.com", "127.0.0.1", "::1"}) {
+				t.Errorf("SANs = %v", analysis.SANs)
+			}
+			if len(analysis.Chain) == 0 || analysis.DaysUntilExpiry <= 0 {
+				t.Errorf("Chain = %v, DaysUntilExpiry = %v", analysis.Chain, analysis.DaysUntilExpiry)
+			}
+		})
+	}
+}
+
+// insecureConnectionState - connects to the tls server without verifying its certificate,
+// the way the pages are fetched, and returns the state of the connection
+func insecureConnectionState(t *testing.T, server *httptest.Server) *tls.ConnectionState {
+	conn, err := tls.Dial("tcp", server.Listener.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("Failed to connect to the tls server: %v", err)
+	}
+	defer conn.Close()
+
+	state := conn.ConnectionState()
+	return &state
+}
+
+// newExpiredCertificate - creates a self-signed certificate for expired.test which expired a day ago
+func newExpiredCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate the key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "expired.test"},
+		DNSNames:     []string{"expired.test"},
+		NotBefore:    time.Now().Add(-48 * time.Hour),
+		NotAfter:     time.Now().Add(-24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create the certificate: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestDetectTLSDetailsUnverifiedConnection(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+	utils := NewWebAnalyzerUtils(logger, &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1})
+
+	untrustedServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer untrustedServer.Close()
+
+	expiredServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	expiredServer.TLS = &tls.Config{Certificates: []tls.Certificate{newExpiredCertificate(t)}}
+	expiredServer.StartTLS()
+	defer expiredServer.Close()
+
+	tests := []struct {
+		name               string
+		server             *httptest.Server
+		host               string
+		expectedMatch      bool
+		expectedIssueCodes []string
+	}{
+		{
+			name:               "Untrusted Certificate",
+			server:             untrustedServer,
+			host:               "example.com",
+			expectedMatch:      true,
+			expectedIssueCodes: []string{"untrusted_certificate"},
+		},
+		{
+			name:               "Untrusted Certificate With Hostname Mismatch",
+			server:             untrustedServer,
+			host:               "localhost",
+			expectedMatch:      false,
+			expectedIssueCodes: []string{"untrusted_certificate", "hostname_mismatch"},
+		},
+		{
+			name:               "Expired Self-Signed Certificate",
+			server:             expiredServer,
+			host:               "expired.test",
+			expectedMatch:      true,
+			expectedIssueCodes: []string{"untrusted_certificate", "certificate_expired"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := utils.DetectTLSDetails(context.Background(), insecureConnectionState(t, tt.server), tt.host)
+
+			if analysis.Trusted {
+				t.Errorf("Trusted = %v, want false", analysis.Trusted)
+			}
+			if analysis.HostnameMatch != tt.expectedMatch {
+				t.Errorf("HostnameMatch = %v, want %v", analysis.HostnameMatch, tt.expectedMatch)
+			}
+			if codes := issueCodes(analysis.Issues); !reflect.DeepEqual(codes, tt.expectedIssueCodes) {
+				t.Errorf("Issues = %v, want %v", codes, tt.expectedIssueCodes)
+			}
+		})
+	}
+}
+
+func TestVerifyCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to connect to the tls server: %v", err)
+	}
+	resp.Body.Close()
+
+	tests := []struct {
+		name          string
+		state         *tls.ConnectionState
+		host          string
+		expectedValid bool
+	}{
+		{
+			name:          "Not TLS",
+			state:         nil,
+			host:          "example.com",
+			expectedValid: true,
+		},
+		{
+			name:          "Verified Connection",
+			state:         resp.TLS,
+			host:          "example.com",
+			expectedValid: true,
+		},
+		{
+			name:          "Verified Connection With Hostname Mismatch",
+			state:         resp.TLS,
+			host:          "other.test",
+			expectedValid: false,
+		},
+		{
+			name:          "Unverified Connection With Untrusted Certificate",
+			state:         insecureConnectionState(t, server),
+			host:          "example.com",
+			expectedValid: false,
+		},
+		{
+			name:          "No Certificate",
+			state:         &tls.ConnectionState{},
+			host:          "example.com",
+			expectedValid: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyCertificate(tt.state, tt.host)
+			if (err == nil) != tt.expectedValid {
+				t.Errorf("VerifyCertificate() error = %v, want valid %v", err, tt.expectedValid)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package web_analyzer_utils
+
+import (
+	_ "embed"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"strings"
+)
+
+//go:embed trackers.yaml
+var defaultTrackerList []byte
+
+// loadTrackerList - reads the tracker list from the given path, or the embedded list when the path is empty
+func loadTrackerList(path string) (map[string]string, error) {
+	if path == "" {
+		return parseTrackerList(defaultTrackerList)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseTrackerList(data)
+}
+
+// parseTrackerList - parses a yaml list of domains grouped by category and maps each domain to its category
+func parseTrackerList(data []byte) (map[string]string, error) {
+	var categories map[string][]string
+	if err := yaml.Unmarshal(data, &categories); err != nil {
+		return nil, fmt.Errorf("invalid tracker list: %w", err)
+	}
+
+	trackers := make(map[string]string)
+	for category, domains := range categories {
+		for _, domain := range domains {
+			domain = strings.Trim(strings.ToLower(strings.TrimSpace(domain)), ".")
+			if domain != "" {
+				trackers[domain] = category
+			}
+		}
+	}
+	return trackers, nil
+}
+
+// lookupDomain - returns the value of the host, or of the closest parent domain of the host, in the domain map.
+// returns an empty string when neither the host nor its parent domains are in the map
+func lookupDomain(domains map[string]string, host string) string {
+	for domain := host; domain != ""; {
+		if value, ok := domains[domain]; ok {
+			return value
+		}
+		_, parent, found := strings.Cut(domain, ".")
+		if !found {
+			break
+		}
+		domain = parent
+	}
+	return ""
+}
@@ -2,15 +2,44 @@ package web_analyzer_utils
 
 import (
 	"context"
+	"crypto/tls"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
 	"github.com/PuerkitoBio/goquery"
+	"net/http"
 	"net/url"
 )
 
 type WebAnalyzerUtils interface {
-	DetectHTMLVersion(ctx context.Context, body string) string
+	DetectCookies(ctx context.Context, header http.Header) response_dtos.CookieAnalysis
+	DetectHTMLVersion(ctx context.Context, body []byte) response_dtos.Doctype
 	DetectPageTitle(ctx context.Context, doc *goquery.Document) string
 	DetectLoginForm(ctx context.Context, doc *goquery.Document) bool
+	DetectAuthentication(ctx context.Context, doc *goquery.Document, pageURL *url.URL) response_dtos.AuthenticationAnalysis
+	DetectForms(ctx context.Context, doc *goquery.Document, pageURL *url.URL) response_dtos.FormAnalysis
 	DetectHeaders(ctx context.Context, doc *goquery.Document, typesOfHeadings [6]string) map[string]int
-	DetectLinks(ctx context.Context, doc *goquery.Document, host string) (int, int, []string)
-	IsLinksAccessible(ctx context.Context, links []string, base *url.URL) int
+	DetectHeadingOutline(ctx context.Context, doc *goquery.Document) response_dtos.HeadingOutline
+	DetectLinks(ctx context.Context, doc *goquery.Document, pageURL *url.URL) (int, int, map[string]int, []string)
+	DetectResources(ctx context.Context, doc *goquery.Document, pageURL *url.URL) response_dtos.ResourceInventory
+	DetectSEOMetadata(ctx context.Context, doc *goquery.Document, pageURL *url.URL) response_dtos.SEOAnalysis
+	DetectStructuredData(ctx context.Context, doc *goquery.Document) response_dtos.StructuredData
+	DetectAccessibilityIssues(ctx context.Context, doc *goquery.Document) response_dtos.AccessibilityAnalysis
+	DetectMixedContent(ctx context.Context, doc *goquery.Document, pageURL *url.URL) response_dtos.MixedContentAnalysis
+	DetectRedirectChainIssues(ctx context.Context, hops []response_dtos.RedirectHop, finalURL string) response_dtos.RedirectAnalysis
+	DetectSecurityHeaders(ctx context.Context, header http.Header, pageURL *url.URL) response_dtos.SecurityHeadersAnalysis
+	DetectTLSDetails(ctx context.Context, state *tls.ConnectionState, host string) response_dtos.TLSAnalysis
+	DetectTechnologies(ctx context.Context, doc *goquery.Document, header http.Header) []response_dtos.Technology
+	DetectThirdPartyDomains(ctx context.Context, pageURL *url.URL, links []string, inventory response_dtos.ResourceInventory) response_dtos.ThirdPartyAnalysis
+	IsLinksAccessible(ctx context.Context, links []string, base *url.URL) LinkCheckReport
+}
+
+// LinkCheckReport - result of a link accessibility check.
+// InaccessibleLinks, MalformedLinks, RestrictedLinks and RateLimitedLinks are the counts of links with the
+// inaccessible, malformed, restricted and rate_limited statuses
+// and Links holds the per link results in the same order as the checked links
+type LinkCheckReport struct {
+	InaccessibleLinks int
+	MalformedLinks    int
+	RestrictedLinks   int
+	RateLimitedLinks  int
+	Links             []response_dtos.LinkStatus
 }
@@ -2,64 +2,131 @@ package web_analyzer_utils
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"github.com/DaminduDilsara/web-analyzer/configurations"
 	"github.com/DaminduDilsara/web-analyzer/internal/log_utils"
+	"github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
 	"github.com/PuerkitoBio/goquery"
+	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 )
 
 const webAnalyzerUtilsLogPrefix = "web_analyzer_utils_impl"
 
+// statuses of a checked link
+const (
+	LinkStatusAccessible   = "accessible"
+	LinkStatusInaccessible = "inaccessible"
+	LinkStatusMalformed    = "malformed"
+	LinkStatusRestricted   = "restricted"
+	LinkStatusRateLimited  = "rate_limited"
+)
+
+// defaultLinkCheckMaxRedirects - number of redirects the link checker follows when
+// webAnalyzerConfig.LinkCheckMaxRedirects is not set
+const defaultLinkCheckMaxRedirects = 10
+
+// defaultMaxRetryAfter - longest time the link checker waits for a host which answered with a Retry-After header
+// when webAnalyzerConfig.MaxRetryAfterSeconds is not set
+const defaultMaxRetryAfter = 10 * time.Second
+
+// errTooManyLinkRedirects - returned by the link checker client when a link redirects more than the configured limit
+var errTooManyLinkRedirects = errors.New("too many redirects")
+
+// headRejectedStatuses - statuses servers answer a HEAD request with while the resource may still be available to a GET
+var headRejectedStatuses = map[int]bool{
+	http.StatusForbidden:        true,
+	http.StatusMethodNotAllowed: true,
+	http.StatusNotImplemented:   true,
+}
+
+// severities of the issues reported by the analyzers
+const (
+	IssueSeverityError   = "error"
+	IssueSeverityWarning = "warning"
+	IssueSeverityInfo    = "info"
+)
+
+// classes of errors which made a link inaccessible
+const (
+	LinkErrorDNS               = "dns"
+	LinkErrorTLS               = "tls"
+	LinkErrorTimeout           = "timeout"
+	LinkErrorConnectionRefused = "connection_refused"
+	LinkErrorConnectionReset   = "connection_reset"
+	LinkErrorRedirect          = "3xx"
+	LinkErrorClientError       = "4xx"
+	LinkErrorServerError       = "5xx"
+	LinkErrorUnknown           = "unknown"
+)
+
 type webAnalyzerUtilsImpl struct {
-	logger            log_utils.LoggerInterface
-	webAnalyzerConfig *configurations.WebAnalyzerConfigurations
+	logger             log_utils.LoggerInterface
+	webAnalyzerConfig  *configurations.WebAnalyzerConfigurations
+	trackers           map[string]string
+	technologies       []technology
+	linkClient         *http.Client
+	limiter            *hostLimiter
+	retryPolicy        *RetryPolicy
+	accessibleStatuses map[int]bool
 }
 
+// NewWebAnalyzerUtils - creates the web analyzer utils and loads the tracker list from webAnalyzerConfig.TrackerListPath
+// and the technology rules from webAnalyzerConfig.TechnologyRulesPath.
+// the embedded list and rules are used when no path is configured or the configured file cannot be loaded.
+// a single http client is shared by all the link checks so the connections are reused between them,
+// and a single host limiter so the limits of a host hold across the concurrent analyses
 func NewWebAnalyzerUtils(
 	logger log_utils.LoggerInterface,
 	webAnalyzerConfig *configurations.WebAnalyzerConfigurations,
 ) WebAnalyzerUtils {
-	return &webAnalyzerUtilsImpl{
-		logger:            logger,
-		webAnalyzerConfig: webAnalyzerConfig,
+	trackers, err := loadTrackerList(webAnalyzerConfig.TrackerListPath)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to load the tracker list from %v, using the embedded list", webAnalyzerConfig.TrackerListPath), err, log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+		trackers, _ = parseTrackerList(defaultTrackerList)
 	}
-}
 
-// DetectHTMLVersion - detects the html version of the web page using version strings
-func (w *webAnalyzerUtilsImpl) DetectHTMLVersion(ctx context.Context, body string) string {
-	body = strings.ToLower(body)
+	technologies, err := loadTechnologyRules(webAnalyzerConfig.TechnologyRulesPath)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to load the technology rules from %v, using the embedded rules", webAnalyzerConfig.TechnologyRulesPath), err, log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+		technologies, _ = parseTechnologyRules(defaultTechnologyRules)
+	}
 
-	htmlVersion := ""
+	accessibleStatuses := make(map[int]bool)
+	for _, statusCode := range webAnalyzerConfig.AccessibleStatusCodes {
+		accessibleStatuses[statusCode] = true
+	}
 
-	switch {
-	case strings.Contains(body, "<!doctype html>"):
-		htmlVersion = "HTML 5"
-	case strings.Contains(body, "-//w3c//dtd html 4.01 frameset//en"):
-		htmlVersion = "HTML 4.01 Frameset"
-	case strings.Contains(body, "-//w3c//dtd html 4.01 transitional//en"):
-		htmlVersion = "HTML 4.01 Transitional"
-	case strings.Contains(body, "-//w3c//dtd html 4.01//en"):
-		htmlVersion = "HTML 4.01 Strict"
-	case strings.Contains(body, "-//w3c//dtd xhtml 1.1//en"):
-		htmlVersion = "XHTML 1.1"
-	case strings.Contains(body, "-//w3c//dtd xhtml 1.0 frameset//en"):
-		htmlVersion = "XHTML 1.0 Frameset"
-	case strings.Contains(body, "-//w3c//dtd xhtml 1.0 transitional//en"):
-		htmlVersion = "XHTML 1.0 Transitional"
-	case strings.Contains(body, "-//w3c//dtd xhtml 1.0 strict//en"):
-		htmlVersion = "XHTML 1.0 Strict"
-	default:
-		htmlVersion = "Unknown"
+	return &webAnalyzerUtilsImpl{
+		logger:             logger,
+		webAnalyzerConfig:  webAnalyzerConfig,
+		trackers:           trackers,
+		technologies:       technologies,
+		linkClient:         newLinkCheckClient(webAnalyzerConfig.LinkCheckMaxRedirects),
+		limiter:            newHostLimiter(webAnalyzerConfig.MaxLinkChecksPerHost, webAnalyzerConfig.LinkCheckRequestsPerSecond, maxRetryAfter(webAnalyzerConfig)),
+		retryPolicy:        NewRetryPolicy(webAnalyzerConfig),
+		accessibleStatuses: accessibleStatuses,
 	}
+}
+
+// DetectHTMLVersion - parses the doctype from the raw bytes of the web page and detects the html version,
+// the public and system identifiers and the quirks mode the page is rendered in
+func (w *webAnalyzerUtilsImpl) DetectHTMLVersion(ctx context.Context, body []byte) response_dtos.Doctype {
+	doctype := parseDoctype(body)
 
-	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified the document html version as %v", htmlVersion), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified the document html version as %v in %v mode", doctype.Version, doctype.Mode), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
 
-	return htmlVersion
+	return doctype
 }
 
 // DetectPageTitle - detects the title of the web page
@@ -102,21 +169,36 @@ func (w *webAnalyzerUtilsImpl) DetectHeaders(ctx context.Context, doc *goquery.D
 	return headers
 }
 
-// DetectLinks - detects the internal, external link counts and returns an array of all existing links in the web page
-func (w *webAnalyzerUtilsImpl) DetectLinks(ctx context.Context, doc *goquery.Document, host string) (int, int, []string) {
-	var allLinks []string
+// DetectLinks - resolves the links of the web page against the page url, honoring <base href>, and returns
+// - the count of internal links, which are in the scope of the page under webAnalyzerConfig.LinkScope
+// - the count of external links
+// - the count of links with a non-http scheme such as mailto, tel, ftp and data, by scheme
+// - the http links as they are written in the page, to be checked by IsLinksAccessible
+// links to a fragment of the same page are ignored and links which cannot be parsed are returned without being counted
+func (w *webAnalyzerUtilsImpl) DetectLinks(ctx context.Context, doc *goquery.Document, pageURL *url.URL) (int, int, map[string]int, []string) {
+	allLinks := []string{}
+	nonHTTPLinks := make(map[string]int)
 	internalLinks, externalLinks := 0, 0
+	baseURL := DocumentBaseURL(doc, pageURL)
 
 	doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
-		href, exists := s.Attr("href")
-		if !exists || href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(href, "javascript") {
+		link := strings.TrimSpace(s.AttrOr("href", ""))
+		if link == "" || strings.HasPrefix(link, "#") {
 			return
 		}
 
-		link := strings.TrimSpace(href)
-		isInternal := strings.HasPrefix(link, "/") && !strings.HasPrefix(link, "//") || strings.Contains(link, host)
+		linkURL, err := resolveReference(baseURL, link)
+		if err != nil {
+			allLinks = append(allLinks, link)
+			return
+		}
+
+		if linkURL.Scheme != "http" && linkURL.Scheme != "https" {
+			nonHTTPLinks[linkURL.Scheme]++
+			return
+		}
 
-		if isInternal {
+		if IsInScope(w.webAnalyzerConfig.LinkScope, pageURL, linkURL) {
 			internalLinks++
 		} else {
 			externalLinks++
@@ -125,63 +207,274 @@ func (w *webAnalyzerUtilsImpl) DetectLinks(ctx context.Context, doc *goquery.Doc
 	})
 
 	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified the internal link count as %v and external link count as %v", internalLinks, externalLinks), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
-	w.logger.InfoWithContext(ctx, fmt.Sprintf("found %v links totally", len(allLinks)), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("found %v links totally and non-http links %v", len(allLinks), nonHTTPLinks), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
 
-	return internalLinks, externalLinks, allLinks
+	return internalLinks, externalLinks, nonHTTPLinks, allLinks
 }
 
-// IsLinksAccessible - checks a list of links and returns the count of inaccessible links
+// IsLinksAccessible - checks a list of links and returns a report with the result of each link
+// and the count of inaccessible, malformed, restricted and rate limited links.
+// the links are resolved against base, which should be the base url of the document (see DocumentBaseURL).
 // uses a worker group of size webAnalyzerConfig.MaxLinkAccessCheckerWorkerCount to keep
-// the number of go routines from increasing uncontrollably
-func (w *webAnalyzerUtilsImpl) IsLinksAccessible(ctx context.Context, links []string, base *url.URL) int {
+// the number of go routines from increasing uncontrollably, and the host limiter shared by all the calls
+// which allows up to webAnalyzerConfig.MaxLinkChecksPerHost requests in flight and
+// webAnalyzerConfig.LinkCheckRequestsPerSecond requests per second to each host
+func (w *webAnalyzerUtilsImpl) IsLinksAccessible(ctx context.Context, links []string, base *url.URL) LinkCheckReport {
 
 	workers := make(chan struct{}, w.webAnalyzerConfig.MaxLinkAccessCheckerWorkerCount)
 	var wg sync.WaitGroup
-	resultChan := make(chan int, len(links))
+	results := make([]response_dtos.LinkStatus, len(links))
 
-	for _, link := range links {
+	var checked atomic.Int64
+	reportLinkProgress(ctx, 0, len(links))
+
+	for i, link := range links {
 		wg.Add(1)
 		workers <- struct{}{} // acquire worker
 
-		go func(link string) {
+		go func(i int, link string) {
 			defer wg.Done()
 			defer func() { <-workers }() // release worker
 
-			fullURL := w.normalizeURL(link, base)
+			results[i] = w.checkLink(ctx, link, base)
+			reportLinkProgress(ctx, int(checked.Add(1)), len(links))
+		}(i, link)
+	}
 
-			client := &http.Client{Timeout: 5 * time.Second}
-			resp, err := client.Head(fullURL)
-			if (err != nil) || (resp.StatusCode < http.StatusOK) || (resp.StatusCode >= http.StatusMultipleChoices) {
-				resultChan <- 1
-			} else {
-				resultChan <- 0
-			}
-			if resp != nil {
+	wg.Wait()
+
+	report := LinkCheckReport{Links: results}
+	for _, result := range results {
+		switch result.Status {
+		case LinkStatusInaccessible:
+			report.InaccessibleLinks++
+		case LinkStatusMalformed:
+			report.MalformedLinks++
+		case LinkStatusRestricted:
+			report.RestrictedLinks++
+		case LinkStatusRateLimited:
+			report.RateLimitedLinks++
+		}
+	}
+
+	w.logger.InfoWithContext(ctx, fmt.Sprintf("identified %v inaccessible, %v malformed, %v restricted and %v rate limited links out of %v", report.InaccessibleLinks, report.MalformedLinks, report.RestrictedLinks, report.RateLimitedLinks, len(links)), log_utils.SetLogFile(webAnalyzerUtilsLogPrefix))
+
+	return report
+}
+
+// checkLink - sends a HEAD request to the link and records the status code, latency, number of attempts and
+// the final url after redirects. when the server rejects the HEAD request with 403, 405 or 501 the
+// link is requested again with a GET for its first byte, and the attempts of both requests are counted. the link is accessible when the status code is
+// in webAnalyzerConfig.AccessibleStatusCodes, or is a 2xx when none are configured. 401 and 403 are
+// reported as restricted, 429 as rate limited and the other failures are classified using the LinkError* classes.
+// links to a host which asked to wait longer than webAnalyzerConfig.MaxRetryAfterSeconds are reported as rate limited
+// and links which cannot be resolved to a http url are reported as malformed without a request
+func (w *webAnalyzerUtilsImpl) checkLink(ctx context.Context, link string, base *url.URL) response_dtos.LinkStatus {
+	result := response_dtos.LinkStatus{
+		Href:   link,
+		Status: LinkStatusInaccessible,
+	}
+
+	fullURL, err := w.normalizeURL(link, base)
+	if err != nil {
+		result.Status = LinkStatusMalformed
+		result.Error = err.Error()
+		return result
+	}
+	result.Url = fullURL
+	result.Method = http.MethodHead
+
+	resp, err := w.sendLinkRequest(ctx, &result)
+	if err == nil && headRejectedStatuses[resp.StatusCode] && !w.isAccessibleStatus(http.MethodHead, resp.StatusCode) {
+		resp.Body.Close()
+		result.Method = http.MethodGet
+		resp, err = w.sendLinkRequest(ctx, &result)
+	}
+
+	if resp != nil {
+		defer resp.Body.Close()
+		result.StatusCode = resp.StatusCode
+		result.FinalUrl = resp.Request.URL.String()
+	}
+	if errors.Is(err, errHostRateLimited) {
+		result.Status = LinkStatusRateLimited
+		result.Error = err.Error()
+		return result
+	}
+	if err != nil {
+		result.ErrorClass = classifyLinkError(err)
+		return result
+	}
+
+	switch {
+	case w.isAccessibleStatus(result.Method, resp.StatusCode):
+		result.Status = LinkStatusAccessible
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		result.Status = LinkStatusRestricted
+	case resp.StatusCode == http.StatusTooManyRequests:
+		result.Status = LinkStatusRateLimited
+	default:
+		result.ErrorClass = classifyStatusCode(resp.StatusCode)
+	}
+	return result
+}
+
+// sendLinkRequest - sends a request with the method of the result to its url using the link checker client once
+// the host limiter allows it, adds the time spent on the request to the latency of the result and its
+// attempts to the attempts of the result. failed requests are sent again as the retry policy allows, after its backoff.
+// GET requests ask only for the first byte of the resource so the body is not downloaded.
+// a 429 or 503 response with a Retry-After header pauses the host, and the request is sent once more
+// after the pause when it is not longer than the limiter waits.
+// when the redirect limit is reached the last redirect response is returned along with the error
+func (w *webAnalyzerUtilsImpl) sendLinkRequest(ctx context.Context, result *response_dtos.LinkStatus) (*http.Response, error) {
+	attempt := 0
+	retriedAfterPause := false
+
+	for {
+		req, err := http.NewRequestWithContext(ctx, result.Method, result.Url, nil)
+		if err != nil {
+			return nil, err
+		}
+		if result.Method == http.MethodGet {
+			req.Header.Set("Range", "bytes=0-0")
+		}
+		host := strings.ToLower(req.URL.Host)
+
+		release, err := w.limiter.acquire(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		attempt++
+		result.Attempts++
+		start := time.Now()
+		resp, err := w.linkClient.Do(req)
+		result.LatencyMs += time.Since(start).Milliseconds()
+		release()
+
+		statusCode := 0
+		if err == nil {
+			statusCode = resp.StatusCode
+		}
+
+		if statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable {
+			if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				w.limiter.pause(host, time.Now().Add(wait))
+				if retriedAfterPause || wait > w.limiter.maxWait {
+					return resp, nil
+				}
+				retriedAfterPause = true
 				resp.Body.Close()
+				continue
 			}
-		}(link)
+		}
+
+		if ctx.Err() != nil || !w.retryPolicy.ShouldRetry(attempt, statusCode, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if err := SleepContext(ctx, w.retryPolicy.Backoff(attempt)); err != nil {
+			return nil, err
+		}
 	}
+}
 
-	go func() {
-		wg.Wait()
-		close(resultChan)
-	}()
+// maxRetryAfter - returns the longest time to wait for a host which answered with a Retry-After header
+func maxRetryAfter(webAnalyzerConfig *configurations.WebAnalyzerConfigurations) time.Duration {
+	if webAnalyzerConfig.MaxRetryAfterSeconds <= 0 {
+		return defaultMaxRetryAfter
+	}
+	return time.Duration(webAnalyzerConfig.MaxRetryAfterSeconds) * time.Second
+}
+
+// isAccessibleStatus - checks if the status code of a link response counts as accessible.
+// the answers to a ranged GET, 206 for a partial body and 416 for an empty resource, count as a 200
+func (w *webAnalyzerUtilsImpl) isAccessibleStatus(method string, statusCode int) bool {
+	if method == http.MethodGet && (statusCode == http.StatusPartialContent || statusCode == http.StatusRequestedRangeNotSatisfiable) {
+		statusCode = http.StatusOK
+	}
+	if len(w.accessibleStatuses) > 0 {
+		return w.accessibleStatuses[statusCode]
+	}
+	return statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices
+}
 
-	inaccessibleLinkCount := 0
-	for result := range resultChan {
-		inaccessibleLinkCount += result
+// newLinkCheckClient - creates the http client of the link checker which follows up to maxRedirects redirects.
+// defaultLinkCheckMaxRedirects is used when maxRedirects is not positive
+func newLinkCheckClient(maxRedirects int) *http.Client {
+	if maxRedirects <= 0 {
+		maxRedirects = defaultLinkCheckMaxRedirects
 	}
 
-	return inaccessibleLinkCount
+	return &http.Client{
+		Timeout: 5 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxRedirects {
+				return errTooManyLinkRedirects
+			}
+			return nil
+		},
+	}
 }
 
-// check the prefix of links and normalize them by adding host name for internal links
-func (w *webAnalyzerUtilsImpl) normalizeURL(link string, base *url.URL) string {
-	if strings.HasPrefix(link, "//") {
-		return base.Scheme + ":" + link
+// normalizeURL - resolves the trimmed link against the base url and drops the fragment, which is never sent
+// to the server. returns an error when the link cannot be parsed or does not resolve to a http url with a host
+func (w *webAnalyzerUtilsImpl) normalizeURL(link string, base *url.URL) (string, error) {
+	linkURL, err := resolveReference(base, link)
+	if err != nil {
+		return "", fmt.Errorf("invalid url: %w", err)
+	}
+	if linkURL.Scheme != "http" && linkURL.Scheme != "https" {
+		return "", fmt.Errorf("unsupported scheme %q", linkURL.Scheme)
+	}
+	if linkURL.Host == "" {
+		return "", errors.New("missing host")
 	}
-	if strings.HasPrefix(link, "/") {
-		return base.Scheme + "://" + base.Host + link
+
+	linkURL.Fragment = ""
+	linkURL.RawFragment = ""
+	return linkURL.String(), nil
+}
+
+// classifyLinkError - maps a transport error returned by the http client to a link error class
+func classifyLinkError(err error) string {
+	var dnsErr *net.DNSError
+	var certVerificationErr *tls.CertificateVerificationError
+	var recordHeaderErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var hostnameErr x509.HostnameError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var certInvalidErr x509.CertificateInvalidError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, errTooManyLinkRedirects):
+		return LinkErrorRedirect
+	case errors.As(err, &dnsErr):
+		return LinkErrorDNS
+	case errors.As(err, &certVerificationErr), errors.As(err, &recordHeaderErr), errors.As(err, &alertErr),
+		errors.As(err, &hostnameErr), errors.As(err, &unknownAuthorityErr), errors.As(err, &certInvalidErr):
+		return LinkErrorTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return LinkErrorTimeout
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return LinkErrorConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return LinkErrorConnectionReset
+	}
+	return LinkErrorUnknown
+}
+
+// classifyStatusCode - maps a non 2xx status code to a link error class
+func classifyStatusCode(statusCode int) string {
+	switch {
+	case statusCode >= http.StatusInternalServerError:
+		return LinkErrorServerError
+	case statusCode >= http.StatusBadRequest:
+		return LinkErrorClientError
+	case statusCode >= http.StatusMultipleChoices:
+		return LinkErrorRedirect
 	}
-	return link
+	return LinkErrorUnknown
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -19,62 +20,123 @@ func TestDetectHTMLVersion(t *testing.T) {
 	utils := NewWebAnalyzerUtils(logger, config)
 
 	tests := []struct {
-		name     string
-		input    string
-		expected string
+		name         string
+		input        string
+		expected     string
+		expectedMode string
 	}{
 		{
-			name:     "HTML 5",
-			input:    "<!DOCTYPE html>",
-			expected: "HTML 5",
+			name:         "HTML 5",
+			input:        "<!DOCTYPE html>",
+			expected:     "HTML 5",
+			expectedMode: "no-quirks",
+		},
+		{
+			name:         "HTML 4.01 Frameset",
+			input:        "<!DOCTYPE HTML PUBLIC \"-//W3C//DTD HTML 4.01 Frameset//EN\">",
+			expected:     "HTML 4.01 Frameset",
+			expectedMode: "quirks",
+		},
+		{
+			name:         "HTML 4.01 Transitional",
+			input:        "<!DOCTYPE HTML PUBLIC \"-//W3C//DTD HTML 4.01 Transitional//EN\">",
+			expected:     "HTML 4.01 Transitional",
+			expectedMode: "quirks",
+		},
+		{
+			name:         "HTML 4.01 Strict",
+			input:        "<!DOCTYPE HTML PUBLIC \"-//W3C//DTD HTML 4.01//EN\">",
+			expected:     "HTML 4.01 Strict",
+			expectedMode: "no-quirks",
+		},
+		{
+			name:         "XHTML 1.1",
+			input:        "<!DOCTYPE html PUBLIC \"-//W3C//DTD XHTML 1.1//EN\">",
+			expected:     "XHTML 1.1",
+			expectedMode: "no-quirks",
+		},
+		{
+			name:         "XHTML 1.0 Frameset",
+			input:        "<!DOCTYPE html PUBLIC \"-//W3C//DTD XHTML 1.0 Frameset//EN\">",
+			expected:     "XHTML 1.0 Frameset",
+			expectedMode: "limited-quirks",
+		},
+		{
+			name:         "XHTML 1.0 Transitional",
+			input:        "<!DOCTYPE html PUBLIC \"-//W3C//DTD XHTML 1.0 Transitional//EN\">",
+			expected:     "XHTML 1.0 Transitional",
+			expectedMode: "limited-quirks",
+		},
+		{
+			name:         "XHTML 1.0 Strict",
+			input:        "<!DOCTYPE html PUBLIC \"-//W3C//DTD XHTML 1.0 Strict//EN\">",
+			expected:     "XHTML 1.0 Strict",
+			expectedMode: "no-quirks",
 		},
 		{
-			name:     "HTML 4.01 Frameset",
-			input:    "<!DOCTYPE HTML PUBLIC \"-//W3C//DTD HTML 4.01 Frameset//EN\">",
-			expected: "HTML 4.01 Frameset",
+			name:         "Unknown Version",
+			input:        "<html><head></head><body></body></html>",
+			expected:     "Unknown",
+			expectedMode: "quirks",
 		},
 		{
-			name:     "HTML 4.01 Transitional",
-			input:    "<!DOCTYPE HTML PUBLIC \"-//W3C//DTD HTML 4.01 Transitional//EN\">",
-			expected: "HTML 4.01 Transitional",
+			name:         "HTML 4.01 Transitional With System Identifier",
+			input:        "<!DOCTYPE HTML PUBLIC \"-//W3C//DTD HTML 4.01 Transitional//EN\" \"http://www.w3.org/TR/html4/loose.dtd\">",
+			expected:     "HTML 4.01 Transitional",
+			expectedMode: "limited-quirks",
 		},
 		{
-			name:     "HTML 4.01 Strict",
-			input:    "<!DOCTYPE HTML PUBLIC \"-//W3C//DTD HTML 4.01//EN\">",
-			expected: "HTML 4.01 Strict",
+			name:         "HTML 3.2",
+			input:        "<!DOCTYPE HTML PUBLIC \"-//W3C//DTD HTML 3.2 Final//EN\"><html></html>",
+			expected:     "HTML 3.2",
+			expectedMode: "quirks",
 		},
 		{
-			name:     "XHTML 1.1",
-			input:    "<!DOCTYPE html PUBLIC \"-//W3C//DTD XHTML 1.1//EN\">",
-			expected: "XHTML 1.1",
+			name:         "HTML 2.0",
+			input:        "<!DOCTYPE HTML PUBLIC \"-//IETF//DTD HTML 2.0//EN\">",
+			expected:     "HTML 2.0",
+			expectedMode: "quirks",
 		},
 		{
-			name:     "XHTML 1.0 Frameset",
-			input:    "<!DOCTYPE html PUBLIC \"-//W3C//DTD XHTML 1.0 Frameset//EN\">",
-			expected: "XHTML 1.0 Frameset",
+			name:         "Custom DTD",
+			input:        "<!DOCTYPE html SYSTEM \"https://example.com/custom.dtd\">",
+			expected:     "Custom DTD",
+			expectedMode: "no-quirks",
 		},
 		{
-			name:     "XHTML 1.0 Transitional",
-			input:    "<!DOCTYPE html PUBLIC \"-//W3C//DTD XHTML 1.0 Transitional//EN\">",
-			expected: "XHTML 1.0 Transitional",
+			name:         "HTML 5 With Byte Order Mark",
+			input:        "\ufeff<!DOCTYPE html>",
+			expected:     "HTML 5",
+			expectedMode: "no-quirks",
 		},
 		{
-			name:     "XHTML 1.0 Strict",
-			input:    "<!DOCTYPE html PUBLIC \"-//W3C//DTD XHTML 1.0 Strict//EN\">",
-			expected: "XHTML 1.0 Strict",
+			name:         "HTML 5 Legacy Compat",
+			input:        "<!-- generated --> \n<!doctype HTML SYSTEM 'about:legacy-compat'>",
+			expected:     "HTML 5",
+			expectedMode: "no-quirks",
 		},
 		{
-			name:     "Unknown Version",
-			input:    "<html><head></head><body></body></html>",
-			expected: "Unknown",
+			name:         "Doctype Mentioned in Body Text",
+			input:        "<html><body><p>start your page with <!doctype html></p></body></html>",
+			expected:     "Unknown",
+			expectedMode: "quirks",
+		},
+		{
+			name:         "Malformed Identifiers",
+			input:        "<!DOCTYPE html PUBLIC -//W3C//DTD HTML 4.01//EN>",
+			expected:     "Unknown",
+			expectedMode: "quirks",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := utils.DetectHTMLVersion(context.Background(), tt.input)
-			if result != tt.expected {
-				t.Errorf("DetectHTMLVersion() = %v, want %v", result, tt.expected)
+			result := utils.DetectHTMLVersion(context.Background(), []byte(tt.input))
+			if result.Version != tt.expected {
+				t.Errorf("DetectHTMLVersion() = %v, want %v", result.Version, tt.expected)
+			}
+			if result.Mode != tt.expectedMode {
+				t.Errorf("DetectHTMLVersion() mode = %v, want %v", result.Mode, tt.expectedMode)
 			}
 		})
 	}
@@ -220,59 +282,124 @@ func TestDetectHeaders(t *testing.T) {
 
 func TestDetectLinks(t *testing.T) {
 	logger := log_utils.InitConsoleLogger()
-	config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1}
-	utils := NewWebAnalyzerUtils(logger, config)
 
 	tests := []struct {
 		name             string
 		html             string
-		host             string
+		pageURL          string
+		linkScope        string
 		expectedInternal int
 		expectedExternal int
+		expectedNonHTTP  map[string]int
 		expectedLinks    []string
 	}{
 		{
 			name:             "Internal Links Only",
 			html:             "<html><body><a href='/page1'>Link 1</a><a href='/page2'>Link 2</a></body></html>",
-			host:             "example.com",
+			pageURL:          "https://example.com/",
 			expectedInternal: 2,
 			expectedExternal: 0,
+			expectedNonHTTP:  map[string]int{},
 			expectedLinks:    []string{"/page1", "/page2"},
 		},
 		{
 			name:             "External Links Only",
 			html:             "<html><body><a href='https://google.com'>Google</a><a href='http://github.com'>GitHub</a></body></html>",
-			host:             "example.com",
+			pageURL:          "https://example.com/",
 			expectedInternal: 0,
 			expectedExternal: 2,
+			expectedNonHTTP:  map[string]int{},
 			expectedLinks:    []string{"https://google.com", "http://github.com"},
 		},
 		{
 			name:             "Mixed Internal and External Links",
 			html:             "<html><body><a href='/internal'>Internal</a><a href='https://external.com'>External</a><a href='//example.com/protocol-relative'>Protocol Relative</a></body></html>",
-			host:             "example.com",
+			pageURL:          "https://example.com/",
 			expectedInternal: 2,
 			expectedExternal: 1,
+			expectedNonHTTP:  map[string]int{},
 			expectedLinks:    []string{"/internal", "https://external.com", "//example.com/protocol-relative"},
 		},
 		{
 			name:             "No Links",
 			html:             "<html><body><div>Content</div></body></html>",
-			host:             "example.com",
+			pageURL:          "https://example.com/",
 			expectedInternal: 0,
 			expectedExternal: 0,
+			expectedNonHTTP:  map[string]int{},
 			expectedLinks:    []string{},
 		},
+		{
+			name:             "Relative links and host in the query",
+			html:             "<html><body><a href='about.html'>About</a><a href='../x'>X</a><a href='https://evil.com/?ref=example.com'>Evil</a><a href='#top'>Top</a></body></html>",
+			pageURL:          "https://example.com/docs/page",
+			expectedInternal: 2,
+			expectedExternal: 1,
+			expectedNonHTTP:  map[string]int{},
+			expectedLinks:    []string{"about.html", "../x", "https://evil.com/?ref=example.com"},
+		},
+		{
+			name:             "Base href",
+			html:             "<html><head><base href='https://cdn.example.net/'></head><body><a href='page'>Page</a><a href='https://example.com/home'>Home</a></body></html>",
+			pageURL:          "https://example.com/",
+			expectedInternal: 1,
+			expectedExternal: 1,
+			expectedNonHTTP:  map[string]int{},
+			expectedLinks:    []string{"page", "https://example.com/home"},
+		},
+		{
+			name:             "Non-HTTP schemes",
+			html:             "<html><body><a href='mailto:hi@example.com'>Mail</a><a href='tel:+123'>Call</a><a href='ftp://example.com/f'>FTP</a><a href='data:text/plain,hi'>Data</a><a href=' MAILTO:x@example.com '>Mail</a><a href='/ok'>OK</a></body></html>",
+			pageURL:          "https://example.com/",
+			expectedInternal: 1,
+			expectedExternal: 0,
+			expectedNonHTTP:  map[string]int{"mailto": 2, "tel": 1, "ftp": 1, "data": 1},
+			expectedLinks:    []string{"/ok"},
+		},
+		{
+			name:             "Exact host scope",
+			html:             "<html><body><a href='https://www.example.com/'>WWW</a><a href='https://blog.www.example.com/'>Blog</a><a href='https://shop.example.com/'>Shop</a></body></html>",
+			pageURL:          "https://www.example.com/",
+			linkScope:        LinkScopeExactHost,
+			expectedInternal: 1,
+			expectedExternal: 2,
+			expectedNonHTTP:  map[string]int{},
+			expectedLinks:    []string{"https://www.example.com/", "https://blog.www.example.com/", "https://shop.example.com/"},
+		},
+		{
+			name:             "Subdomains scope",
+			html:             "<html><body><a href='https://www.example.com/'>WWW</a><a href='https://blog.www.example.com/'>Blog</a><a href='https://shop.example.com/'>Shop</a></body></html>",
+			pageURL:          "https://www.example.com/",
+			linkScope:        LinkScopeSubdomains,
+			expectedInternal: 2,
+			expectedExternal: 1,
+			expectedNonHTTP:  map[string]int{},
+			expectedLinks:    []string{"https://www.example.com/", "https://blog.www.example.com/", "https://shop.example.com/"},
+		},
+		{
+			name:             "Registrable domain scope",
+			html:             "<html><body><a href='https://www.example.co.uk/'>WWW</a><a href='https://shop.example.co.uk/'>Shop</a><a href='https://other.co.uk/'>Other</a></body></html>",
+			pageURL:          "https://www.example.co.uk/",
+			linkScope:        LinkScopeRegistrableDomain,
+			expectedInternal: 2,
+			expectedExternal: 1,
+			expectedNonHTTP:  map[string]int{},
+			expectedLinks:    []string{"https://www.example.co.uk/", "https://shop.example.co.uk/", "https://other.co.uk/"},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 1, LinkScope: tt.linkScope}
+			utils := NewWebAnalyzerUtils(logger, config)
+
 			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
 			if err != nil {
 				t.Fatalf("Failed to parse HTML: %v", err)
 			}
+			pageURL, _ := url.Parse(tt.pageURL)
 
-			internal, external, links := utils.DetectLinks(context.Background(), doc, tt.host)
+			internal, external, nonHTTP, links := utils.DetectLinks(context.Background(), doc, pageURL)
 
 			if internal != tt.expectedInternal {
 				t.Errorf("DetectLinks() internal = %v, want %v", internal, tt.expectedInternal)
@@ -282,22 +409,12 @@ func TestDetectLinks(t *testing.T) {
 				t.Errorf("DetectLinks() external = %v, want %v", external, tt.expectedExternal)
 			}
 
-			if len(links) != len(tt.expectedLinks) {
-				t.Errorf("DetectLinks() returned %d links, want %d", len(links), len(tt.expectedLinks))
+			if !reflect.DeepEqual(nonHTTP, tt.expectedNonHTTP) {
+				t.Errorf("DetectLinks() non-http = %v, want %v", nonHTTP, tt.expectedNonHTTP)
 			}
 
-			// Check if all expected links are present
-			for _, expectedLink := range tt.expectedLinks {
-				found := false
-				for _, link := range links {
-					if link == expectedLink {
-						found = true
-						break
-					}
-				}
-				if !found {
-					t.Errorf("DetectLinks() missing expected link: %s", expectedLink)
-				}
+			if !reflect.DeepEqual(links, tt.expectedLinks) {
+				t.Errorf("DetectLinks() links = %v, want %v", links, tt.expectedLinks)
 			}
 		})
 	}
@@ -331,10 +448,11 @@ func TestIsLinksAccessible(t *testing.T) {
 	inaccessibleURL, _ := url.Parse(inaccessibleSrv.URL)
 
 	tests := []struct {
-		name     string
-		links    []string
-		base     *url.URL
-		expected int
+		name              string
+		links             []string
+		base              *url.URL
+		expected          int
+		expectedMalformed int
 	}{
 		{
 			name:     "All Accessible",
@@ -360,14 +478,285 @@ func TestIsLinksAccessible(t *testing.T) {
 			base:     accessibleURL,
 			expected: 1, // only inaccessibleSrv.URL is inaccessible
 		},
+		{
+			name:              "Malformed URLs",
+			links:             []string{"http://[::1", "mailto:someone@example.com", "http://", accessibleSrv.URL},
+			base:              accessibleURL,
+			expected:          0,
+			expectedMalformed: 3,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			count := utils.IsLinksAccessible(context.Background(), tt.links, tt.base)
-			if count != tt.expected {
-				t.Errorf("IsLinksAccessible() = %v, want %v", count, tt.expected)
+			report := utils.IsLinksAccessible(context.Background(), tt.links, tt.base)
+			if report.InaccessibleLinks != tt.expected {
+				t.Errorf("IsLinksAccessible() = %v, want %v", report.InaccessibleLinks, tt.expected)
+			}
+			if report.MalformedLinks != tt.expectedMalformed {
+				t.Errorf("IsLinksAccessible() malformed = %v, want %v", report.MalformedLinks, tt.expectedMalformed)
+			}
+			if len(report.Links) != len(tt.links) {
+				t.Errorf("IsLinksAccessible() returned %d link results, want %d", len(report.Links), len(tt.links))
 			}
 		})
 	}
 }
+
+func TestIsLinksAccessibleReport(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+	config := &configurations.WebAnalyzerConfigurations{MaxLinkAccessCheckerWorkerCount: 2}
+	utils := NewWebAnalyzerUtils(logger, config)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/broken":
+			w.WriteHeader(http.StatusBadGateway)
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			if r.Header.Get("Range") != "bytes=0-0" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusPartialContent)
+		case "/private":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+		case "/busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer srv.Close()
+
+	// a closed listener gives a free port which refuses connections
+	closedSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closedURL := closedSrv.URL
+	closedSrv.Close()
+
+	base, _ := url.Parse(srv.URL + "/docs/")
+
+	tests := []struct {
+		name               string
+		link               string
+		expectedUrl        string
+		expectedFinalUrl   string
+		expectedStatus     string
+		expectedMethod     string
+		expectedStatusCode int
+		expectedErrorClass string
+	}{
+		{
+			name:               "Redirected Link",
+			link:               "/old",
+			expectedUrl:        srv.URL + "/old",
+			expectedFinalUrl:   srv.URL + "/new",
+			expectedStatus:     LinkStatusAccessible,
+			expectedMethod:     http.MethodHead,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Relative Link",
+			link:               " page.html#intro ",
+			expectedUrl:        srv.URL + "/docs/page.html",
+			expectedFinalUrl:   srv.URL + "/docs/page.html",
+			expectedStatus:     LinkStatusAccessible,
+			expectedMethod:     http.MethodHead,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Parent Relative Link",
+			link:               "../index.html",
+			expectedUrl:        srv.URL + "/index.html",
+			expectedFinalUrl:   srv.URL + "/index.html",
+			expectedStatus:     LinkStatusAccessible,
+			expectedMethod:     http.MethodHead,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:           "Malformed Link",
+			link:           "http://%zz",
+			expectedStatus: LinkStatusMalformed,
+		},
+		{
+			name:               "Client Error",
+			link:               "/missing",
+			expectedUrl:        srv.URL + "/missing",
+			expectedFinalUrl:   srv.URL + "/missing",
+			expectedStatus:     LinkStatusInaccessible,
+			expectedMethod:     http.MethodHead,
+			expectedStatusCode: http.StatusNotFound,
+			expectedErrorClass: LinkErrorClientError,
+		},
+		{
+			name:               "Server Error",
+			link:               "/broken",
+			expectedUrl:        srv.URL + "/broken",
+			expectedFinalUrl:   srv.URL + "/broken",
+			expectedStatus:     LinkStatusInaccessible,
+			expectedMethod:     http.MethodHead,
+			expectedStatusCode: http.StatusBadGateway,
+			expectedErrorClass: LinkErrorServerError,
+		},
+		{
+			name:               "Connection Refused",
+			link:               closedURL,
+			expectedUrl:        closedURL,
+			expectedStatus:     LinkStatusInaccessible,
+			expectedMethod:     http.MethodHead,
+			expectedErrorClass: LinkErrorConnectionRefused,
+		},
+		{
+			name:               "HEAD Rejected Falls Back to Ranged GET",
+			link:               "/no-head",
+			expectedUrl:        srv.URL + "/no-head",
+			expectedFinalUrl:   srv.URL + "/no-head",
+			expectedStatus:     LinkStatusAccessible,
+			expectedMethod:     http.MethodGet,
+			expectedStatusCode: http.StatusPartialContent,
+		},
+		{
+			name:               "Unauthorized Link",
+			link:               "/private",
+			expectedUrl:        srv.URL + "/private",
+			expectedFinalUrl:   srv.URL + "/private",
+			expectedStatus:     LinkStatusRestricted,
+			expectedMethod:     http.MethodHead,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "Forbidden Link",
+			link:               "/forbidden",
+			expectedUrl:        srv.URL + "/forbidden",
+			expectedFinalUrl:   srv.URL + "/forbidden",
+			expectedStatus:     LinkStatusRestricted,
+			expectedMethod:     http.MethodGet,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Rate Limited Link",
+			link:               "/busy",
+			expectedUrl:        srv.URL + "/busy",
+			expectedFinalUrl:   srv.URL + "/busy",
+			expectedStatus:     LinkStatusRateLimited,
+			expectedMethod:     http.MethodHead,
+			expectedStatusCode: http.StatusTooManyRequests,
+		},
+		{
+			name:               "Redirect Loop",
+			link:               "/loop",
+			expectedUrl:        srv.URL + "/loop",
+			expectedFinalUrl:   srv.URL + "/loop",
+			expectedStatus:     LinkStatusInaccessible,
+			expectedMethod:     http.MethodHead,
+			expectedStatusCode: http.StatusFound,
+			expectedErrorClass: LinkErrorRedirect,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := utils.IsLinksAccessible(context.Background(), []string{tt.link}, base)
+			if len(report.Links) != 1 {
+				t.Fatalf("IsLinksAccessible() returned %d link results, want 1", len(report.Links))
+			}
+
+			result := report.Links[0]
+			if result.Href != tt.link {
+				t.Errorf("Href = %v, want %v", result.Href, tt.link)
+			}
+			if result.Url != tt.expectedUrl {
+				t.Errorf("Url = %v, want %v", result.Url, tt.expectedUrl)
+			}
+			if result.FinalUrl != tt.expectedFinalUrl {
+				t.Errorf("FinalUrl = %v, want %v", result.FinalUrl, tt.expectedFinalUrl)
+			}
+			if result.Status != tt.expectedStatus {
+				t.Errorf("Status = %v, want %v", result.Status, tt.expectedStatus)
+			}
+			if result.Method != tt.expectedMethod {
+				t.Errorf("Method = %v, want %v", result.Method, tt.expectedMethod)
+			}
+			if result.StatusCode != tt.expectedStatusCode {
+				t.Errorf("StatusCode = %v, want %v", result.StatusCode, tt.expectedStatusCode)
+			}
+			if result.ErrorClass != tt.expectedErrorClass {
+				t.Errorf("ErrorClass = %v, want %v", result.ErrorClass, tt.expectedErrorClass)
+			}
+		})
+	}
+}
+
+func TestIsLinksAccessibleCriteria(t *testing.T) {
+	logger := log_utils.InitConsoleLogger()
+	config := &configurations.WebAnalyzerConfigurations{
+		MaxLinkAccessCheckerWorkerCount: 2,
+		LinkCheckMaxRedirects:           1,
+		AccessibleStatusCodes:           []int{http.StatusOK, http.StatusNotFound},
+	}
+	utils := NewWebAnalyzerUtils(logger, config)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/one-hop":
+			http.Redirect(w, r, "/", http.StatusFound)
+		case "/two-hops":
+			http.Redirect(w, r, "/one-hop", http.StatusFound)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusNotImplemented)
+				return
+			}
+			w.WriteHeader(http.StatusPartialContent)
+		case "/busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer srv.Close()
+
+	base, _ := url.Parse(srv.URL)
+
+	tests := []struct {
+		name           string
+		link           string
+		expectedStatus string
+	}{
+		{name: "Redirect Within Limit", link: "/one-hop", expectedStatus: LinkStatusAccessible},
+		{name: "Redirect Over Limit", link: "/two-hops", expectedStatus: LinkStatusInaccessible},
+		{name: "Configured Status", link: "/missing", expectedStatus: LinkStatusAccessible},
+		{name: "2xx Not Configured", link: "/empty", expectedStatus: LinkStatusInaccessible},
+		{name: "Partial Content Counts as 200", link: "/no-head", expectedStatus: LinkStatusAccessible},
+		{name: "Rate Limited", link: "/busy", expectedStatus: LinkStatusRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := utils.IsLinksAccessible(context.Background(), []string{tt.link}, base)
+			if report.Links[0].Status != tt.expectedStatus {
+				t.Errorf("Status = %v, want %v", report.Links[0].Status, tt.expectedStatus)
+			}
+		})
+	}
+
+	report := utils.IsLinksAccessible(context.Background(), []string{"/missing", "/busy", "/two-hops", "/busy"}, base)
+	if report.InaccessibleLinks != 1 || report.RateLimitedLinks != 2 || report.RestrictedLinks != 0 {
+		t.Errorf("IsLinksAccessible() counts = %v inaccessible, %v rate limited, %v restricted, want 1, 2, 0", report.InaccessibleLinks, report.RateLimitedLinks, report.RestrictedLinks)
+	}
+}
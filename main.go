@@ -21,9 +21,13 @@ func main() {
 
 	webAnalyzerUtils := web_analyzer_utils.NewWebAnalyzerUtils(logger, conf.WebAnalyzerConfig)
 
-	webAnalyzerService := services.NewWebAnalyzerService(logger, webAnalyzerUtils)
+	webAnalyzerService := services.NewWebAnalyzerService(logger, webAnalyzerUtils, conf.WebAnalyzerConfig, conf.CrawlConfig)
 
-	controller := controllers.NewControllerV1(webAnalyzerService, logger)
+	batchAnalyzerService := services.NewBatchAnalyzerService(logger, webAnalyzerService, conf.BatchConfig)
+
+	analysisJobService := services.NewAnalysisJobService(logger, webAnalyzerService, batchAnalyzerService, conf.JobConfig)
+
+	controller := controllers.NewControllerV1(webAnalyzerService, analysisJobService, logger)
 
 	http.InitServer(logger, conf.AppConfig, controller)
 
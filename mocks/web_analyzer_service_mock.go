@@ -9,6 +9,7 @@ import (
 	url "net/url"
 	reflect "reflect"
 
+	request_dtos "github.com/DaminduDilsara/web-analyzer/internal/schemas/request_dtos"
 	response_dtos "github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -37,16 +38,31 @@ func (m *MockWebAnalyzerService) EXPECT() *MockWebAnalyzerServiceMockRecorder {
 }
 
 // AnalyzeUrl mocks base method.
-func (m *MockWebAnalyzerService) AnalyzeUrl(ctx context.Context, parsedURL *url.URL) (*response_dtos.UrlAnalyzerResponse, error) {
+func (m *MockWebAnalyzerService) AnalyzeUrl(ctx context.Context, parsedURL *url.URL, options request_dtos.AnalyzeOptions) (*response_dtos.UrlAnalyzerResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AnalyzeUrl", ctx, parsedURL)
+	ret := m.ctrl.Call(m, "AnalyzeUrl", ctx, parsedURL, options)
 	ret0, _ := ret[0].(*response_dtos.UrlAnalyzerResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AnalyzeUrl indicates an expected call of AnalyzeUrl.
-func (mr *MockWebAnalyzerServiceMockRecorder) AnalyzeUrl(ctx, parsedURL interface{}) *gomock.Call {
+func (mr *MockWebAnalyzerServiceMockRecorder) AnalyzeUrl(ctx, parsedURL, options interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyzeUrl", reflect.TypeOf((*MockWebAnalyzerService)(nil).AnalyzeUrl), ctx, parsedURL)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyzeUrl", reflect.TypeOf((*MockWebAnalyzerService)(nil).AnalyzeUrl), ctx, parsedURL, options)
+}
+
+// CrawlSite mocks base method.
+func (m *MockWebAnalyzerService) CrawlSite(ctx context.Context, parsedURL *url.URL, request request_dtos.CrawlRequest) (*response_dtos.CrawlResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CrawlSite", ctx, parsedURL, request)
+	ret0, _ := ret[0].(*response_dtos.CrawlResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CrawlSite indicates an expected call of CrawlSite.
+func (mr *MockWebAnalyzerServiceMockRecorder) CrawlSite(ctx, parsedURL, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CrawlSite", reflect.TypeOf((*MockWebAnalyzerService)(nil).CrawlSite), ctx, parsedURL, request)
 }
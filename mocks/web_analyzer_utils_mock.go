@@ -6,9 +6,13 @@ package mocks
 
 import (
 	context "context"
+	tls "crypto/tls"
+	http "net/http"
 	url "net/url"
 	reflect "reflect"
 
+	response_dtos "github.com/DaminduDilsara/web-analyzer/internal/schemas/response_dtos"
+	web_analyzer_utils "github.com/DaminduDilsara/web-analyzer/internal/web_analyzer_utils"
 	goquery "github.com/PuerkitoBio/goquery"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -36,11 +40,67 @@ func (m *MockWebAnalyzerUtils) EXPECT() *MockWebAnalyzerUtilsMockRecorder {
 	return m.recorder
 }
 
+// DetectAccessibilityIssues mocks base method.
+func (m *MockWebAnalyzerUtils) DetectAccessibilityIssues(ctx context.Context, doc *goquery.Document) response_dtos.AccessibilityAnalysis {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectAccessibilityIssues", ctx, doc)
+	ret0, _ := ret[0].(response_dtos.AccessibilityAnalysis)
+	return ret0
+}
+
+// DetectAccessibilityIssues indicates an expected call of DetectAccessibilityIssues.
+func (mr *MockWebAnalyzerUtilsMockRecorder) DetectAccessibilityIssues(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectAccessibilityIssues", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectAccessibilityIssues), ctx, doc)
+}
+
+// DetectAuthentication mocks base method.
+func (m *MockWebAnalyzerUtils) DetectAuthentication(ctx context.Context, doc *goquery.Document, pageURL *url.URL) response_dtos.AuthenticationAnalysis {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectAuthentication", ctx, doc, pageURL)
+	ret0, _ := ret[0].(response_dtos.AuthenticationAnalysis)
+	return ret0
+}
+
+// DetectAuthentication indicates an expected call of DetectAuthentication.
+func (mr *MockWebAnalyzerUtilsMockRecorder) DetectAuthentication(ctx, doc, pageURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectAuthentication", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectAuthentication), ctx, doc, pageURL)
+}
+
+// DetectCookies mocks base method.
+func (m *MockWebAnalyzerUtils) DetectCookies(ctx context.Context, header http.Header) response_dtos.CookieAnalysis {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectCookies", ctx, header)
+	ret0, _ := ret[0].(response_dtos.CookieAnalysis)
+	return ret0
+}
+
+// DetectCookies indicates an expected call of DetectCookies.
+func (mr *MockWebAnalyzerUtilsMockRecorder) DetectCookies(ctx, header interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectCookies", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectCookies), ctx, header)
+}
+
+// DetectForms mocks base method.
+func (m *MockWebAnalyzerUtils) DetectForms(ctx context.Context, doc *goquery.Document, pageURL *url.URL) response_dtos.FormAnalysis {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectForms", ctx, doc, pageURL)
+	ret0, _ := ret[0].(response_dtos.FormAnalysis)
+	return ret0
+}
+
+// DetectForms indicates an expected call of DetectForms.
+func (mr *MockWebAnalyzerUtilsMockRecorder) DetectForms(ctx, doc, pageURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectForms", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectForms), ctx, doc, pageURL)
+}
+
 // DetectHTMLVersion mocks base method.
-func (m *MockWebAnalyzerUtils) DetectHTMLVersion(ctx context.Context, body string) string {
+func (m *MockWebAnalyzerUtils) DetectHTMLVersion(ctx context.Context, body []byte) response_dtos.Doctype {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DetectHTMLVersion", ctx, body)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(response_dtos.Doctype)
 	return ret0
 }
 
@@ -64,20 +124,35 @@ func (mr *MockWebAnalyzerUtilsMockRecorder) DetectHeaders(ctx, doc, typesOfHeadi
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectHeaders", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectHeaders), ctx, doc, typesOfHeadings)
 }
 
+// DetectHeadingOutline mocks base method.
+func (m *MockWebAnalyzerUtils) DetectHeadingOutline(ctx context.Context, doc *goquery.Document) response_dtos.HeadingOutline {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectHeadingOutline", ctx, doc)
+	ret0, _ := ret[0].(response_dtos.HeadingOutline)
+	return ret0
+}
+
+// DetectHeadingOutline indicates an expected call of DetectHeadingOutline.
+func (mr *MockWebAnalyzerUtilsMockRecorder) DetectHeadingOutline(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectHeadingOutline", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectHeadingOutline), ctx, doc)
+}
+
 // DetectLinks mocks base method.
-func (m *MockWebAnalyzerUtils) DetectLinks(ctx context.Context, doc *goquery.Document, host string) (int, int, []string) {
+func (m *MockWebAnalyzerUtils) DetectLinks(ctx context.Context, doc *goquery.Document, pageURL *url.URL) (int, int, map[string]int, []string) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DetectLinks", ctx, doc, host)
+	ret := m.ctrl.Call(m, "DetectLinks", ctx, doc, pageURL)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(int)
-	ret2, _ := ret[2].([]string)
-	return ret0, ret1, ret2
+	ret2, _ := ret[2].(map[string]int)
+	ret3, _ := ret[3].([]string)
+	return ret0, ret1, ret2, ret3
 }
 
 // DetectLinks indicates an expected call of DetectLinks.
-func (mr *MockWebAnalyzerUtilsMockRecorder) DetectLinks(ctx, doc, host interface{}) *gomock.Call {
+func (mr *MockWebAnalyzerUtilsMockRecorder) DetectLinks(ctx, doc, pageURL interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectLinks", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectLinks), ctx, doc, host)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectLinks", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectLinks), ctx, doc, pageURL)
 }
 
 // DetectLoginForm mocks base method.
@@ -94,6 +169,20 @@ func (mr *MockWebAnalyzerUtilsMockRecorder) DetectLoginForm(ctx, doc interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectLoginForm", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectLoginForm), ctx, doc)
 }
 
+// DetectMixedContent mocks base method.
+func (m *MockWebAnalyzerUtils) DetectMixedContent(ctx context.Context, doc *goquery.Document, pageURL *url.URL) response_dtos.MixedContentAnalysis {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectMixedContent", ctx, doc, pageURL)
+	ret0, _ := ret[0].(response_dtos.MixedContentAnalysis)
+	return ret0
+}
+
+// DetectMixedContent indicates an expected call of DetectMixedContent.
+func (mr *MockWebAnalyzerUtilsMockRecorder) DetectMixedContent(ctx, doc, pageURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectMixedContent", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectMixedContent), ctx, doc, pageURL)
+}
+
 // DetectPageTitle mocks base method.
 func (m *MockWebAnalyzerUtils) DetectPageTitle(ctx context.Context, doc *goquery.Document) string {
 	m.ctrl.T.Helper()
@@ -108,11 +197,123 @@ func (mr *MockWebAnalyzerUtilsMockRecorder) DetectPageTitle(ctx, doc interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectPageTitle", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectPageTitle), ctx, doc)
 }
 
+// DetectRedirectChainIssues mocks base method.
+func (m *MockWebAnalyzerUtils) DetectRedirectChainIssues(ctx context.Context, hops []response_dtos.RedirectHop, finalURL string) response_dtos.RedirectAnalysis {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectRedirectChainIssues", ctx, hops, finalURL)
+	ret0, _ := ret[0].(response_dtos.RedirectAnalysis)
+	return ret0
+}
+
+// DetectRedirectChainIssues indicates an expected call of DetectRedirectChainIssues.
+func (mr *MockWebAnalyzerUtilsMockRecorder) DetectRedirectChainIssues(ctx, hops, finalURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectRedirectChainIssues", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectRedirectChainIssues), ctx, hops, finalURL)
+}
+
+// DetectResources mocks base method.
+func (m *MockWebAnalyzerUtils) DetectResources(ctx context.Context, doc *goquery.Document, pageURL *url.URL) response_dtos.ResourceInventory {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectResources", ctx, doc, pageURL)
+	ret0, _ := ret[0].(response_dtos.ResourceInventory)
+	return ret0
+}
+
+// DetectResources indicates an expected call of DetectResources.
+func (mr *MockWebAnalyzerUtilsMockRecorder) DetectResources(ctx, doc, pageURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectResources", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectResources), ctx, doc, pageURL)
+}
+
+// DetectSEOMetadata mocks base method.
+func (m *MockWebAnalyzerUtils) DetectSEOMetadata(ctx context.Context, doc *goquery.Document, pageURL *url.URL) response_dtos.SEOAnalysis {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectSEOMetadata", ctx, doc, pageURL)
+	ret0, _ := ret[0].(response_dtos.SEOAnalysis)
+	return ret0
+}
+
+// DetectSEOMetadata indicates an expected call of DetectSEOMetadata.
+func (mr *MockWebAnalyzerUtilsMockRecorder) DetectSEOMetadata(ctx, doc, pageURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectSEOMetadata", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectSEOMetadata), ctx, doc, pageURL)
+}
+
+// DetectSecurityHeaders mocks base method.
+func (m *MockWebAnalyzerUtils) DetectSecurityHeaders(ctx context.Context, header http.Header, pageURL *url.URL) response_dtos.SecurityHeadersAnalysis {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectSecurityHeaders", ctx, header, pageURL)
+	ret0, _ := ret[0].(response_dtos.SecurityHeadersAnalysis)
+	return ret0
+}
+
+// DetectSecurityHeaders indicates an expected call of DetectSecurityHeaders.
+func (mr *MockWebAnalyzerUtilsMockRecorder) DetectSecurityHeaders(ctx, header, pageURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectSecurityHeaders", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectSecurityHeaders), ctx, header, pageURL)
+}
+
+// DetectStructuredData mocks base method.
+func (m *MockWebAnalyzerUtils) DetectStructuredData(ctx context.Context, doc *goquery.Document) response_dtos.StructuredData {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectStructuredData", ctx, doc)
+	ret0, _ := ret[0].(response_dtos.StructuredData)
+	return ret0
+}
+
+// DetectStructuredData indicates an expected call of DetectStructuredData.
+func (mr *MockWebAnalyzerUtilsMockRecorder) DetectStructuredData(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectStructuredData", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectStructuredData), ctx, doc)
+}
+
+// DetectTLSDetails mocks base method.
+func (m *MockWebAnalyzerUtils) DetectTLSDetails(ctx context.Context, state *tls.ConnectionState, host string) response_dtos.TLSAnalysis {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectTLSDetails", ctx, state, host)
+	ret0, _ := ret[0].(response_dtos.TLSAnalysis)
+	return ret0
+}
+
+// DetectTLSDetails indicates an expected call of DetectTLSDetails.
+func (mr *MockWebAnalyzerUtilsMockRecorder) DetectTLSDetails(ctx, state, host interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectTLSDetails", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectTLSDetails), ctx, state, host)
+}
+
+// DetectTechnologies mocks base method.
+func (m *MockWebAnalyzerUtils) DetectTechnologies(ctx context.Context, doc *goquery.Document, header http.Header) []response_dtos.Technology {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectTechnologies", ctx, doc, header)
+	ret0, _ := ret[0].([]response_dtos.Technology)
+	return ret0
+}
+
+// DetectTechnologies indicates an expected call of DetectTechnologies.
+func (mr *MockWebAnalyzerUtilsMockRecorder) DetectTechnologies(ctx, doc, header interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectTechnologies", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectTechnologies), ctx, doc, header)
+}
+
+// DetectThirdPartyDomains mocks base method.
+func (m *MockWebAnalyzerUtils) DetectThirdPartyDomains(ctx context.Context, pageURL *url.URL, links []string, inventory response_dtos.ResourceInventory) response_dtos.ThirdPartyAnalysis {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectThirdPartyDomains", ctx, pageURL, links, inventory)
+	ret0, _ := ret[0].(response_dtos.ThirdPartyAnalysis)
+	return ret0
+}
+
+// DetectThirdPartyDomains indicates an expected call of DetectThirdPartyDomains.
+func (mr *MockWebAnalyzerUtilsMockRecorder) DetectThirdPartyDomains(ctx, pageURL, links, inventory interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectThirdPartyDomains", reflect.TypeOf((*MockWebAnalyzerUtils)(nil).DetectThirdPartyDomains), ctx, pageURL, links, inventory)
+}
+
 // IsLinksAccessible mocks base method.
-func (m *MockWebAnalyzerUtils) IsLinksAccessible(ctx context.Context, links []string, base *url.URL) int {
+func (m *MockWebAnalyzerUtils) IsLinksAccessible(ctx context.Context, links []string, base *url.URL) web_analyzer_utils.LinkCheckReport {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "IsLinksAccessible", ctx, links, base)
-	ret0, _ := ret[0].(int)
+	ret0, _ := ret[0].(web_analyzer_utils.LinkCheckReport)
 	return ret0
 }
 